@@ -35,7 +35,7 @@ func main() {
 	// Demo tenant ID-- tenants are managed outside of this sytem.
 
 	tenantID := "2c24a2a0-5223-47b7-a572-392aac75993a"
-	registry.AddDeployment(context.Background(), &lti_domain.BaseLTIDeployment{
+	err := registry.AddDeployment(context.Background(), &lti_domain.BaseLTIDeployment{
 		InternalID:    uuid.NewString(),
 		ForTenantID:   tenantID,
 		Issuer:        os.Getenv("LTI_ISSUER"),
@@ -45,6 +45,9 @@ func main() {
 		TokenEndpoint: os.Getenv("LTI_TOKEN_ENDPOINT"),
 		DeploymentID:  os.Getenv("LTI_DEPLOYMENT_ID"),
 	})
+	if err != nil {
+		panic(err)
+	}
 	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
 
 	signVerifier := lti_crypto.NewRS256("kid-demo", priv, &priv.PublicKey, "https://dev.kv.codes/lti/")
@@ -79,6 +82,7 @@ func main() {
 		lti_http.WithLauncher(launcher),
 		lti_http.WithVerifier(signVerifier),
 		lti_http.WithImpostering(imposteringSvc),
+		lti_http.WithCookiePolicy(lti_domain.CookiePolicy{Insecure: os.Getenv("INSECURE_COOKIES") == "true"}),
 	)
 
 	demoMux := http.NewServeMux()
@@ -95,8 +99,9 @@ func main() {
 	http.ListenAndServe(":8888", ltiInstance.CreateRoutes(
 		lti_http.WithProtectedRoutes(
 			lti_ports.ProtectedRoute{
-				Path: "/respond",
-				Role: []lti_domain.Role{lti_domain.MEMBERSHIP_CONTENT_DEV},
+				Path:                   "/respond",
+				Role:                   []lti_domain.Role{lti_domain.MEMBERSHIP_CONTENT_DEV},
+				RequireDeepLinkContext: true,
 				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 					lti_deeplink.ReplyToDeeplink(w, r, signVerifier, []lti_domain.DeepLinkItem{
 						{
@@ -118,8 +123,9 @@ func main() {
 				}),
 			},
 			lti_ports.ProtectedRoute{
-				Path: "/deeplink",
-				Role: []lti_domain.Role{lti_domain.MEMBERSHIP_CONTENT_DEV},
+				Path:                   "/deeplink",
+				Role:                   []lti_domain.Role{lti_domain.MEMBERSHIP_CONTENT_DEV},
+				RequireDeepLinkContext: true,
 				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 					w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
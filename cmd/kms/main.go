@@ -34,7 +34,7 @@ func main() {
 	// Demo tenant ID-- tenants are managed outside of this sytem.
 
 	tenantID := uuid.NewString()
-	registry.AddDeployment(context.Background(), &lti_domain.BaseLTIDeployment{
+	err := registry.AddDeployment(context.Background(), &lti_domain.BaseLTIDeployment{
 		InternalID:    uuid.NewString(),
 		ForTenantID:   tenantID,
 		Issuer:        os.Getenv("LTI_ISSUER"),
@@ -44,6 +44,9 @@ func main() {
 		TokenEndpoint: os.Getenv("LTI_TOKEN_ENDPOINT"),
 		DeploymentID:  os.Getenv("LTI_DEPLOYMENT_ID"),
 	})
+	if err != nil {
+		panic(err)
+	}
 
 	ctx := context.Background()
 
@@ -121,6 +124,7 @@ func main() {
 	ltiInstance := lti_http.NewServer(
 		lti_http.WithLauncher(launcher),
 		lti_http.WithVerifier(signer),
+		lti_http.WithCookiePolicy(lti_domain.CookiePolicy{Insecure: os.Getenv("INSECURE_COOKIES") == "true"}),
 	)
 
 	demoMux := http.NewServeMux()
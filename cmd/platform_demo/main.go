@@ -20,6 +20,7 @@ import (
 	"github.com/vizdos-enterprises/go-lti/lti/lti_launcher"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_logger"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_registration"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_registry"
 )
 
@@ -29,7 +30,6 @@ const (
 )
 
 func main() {
-	// os.Setenv("INSECURE_COOKIES", "true")
 	_ = godotenv.Load()
 
 	logger := lti_logger.NewSlogLogger()
@@ -39,7 +39,7 @@ func main() {
 
 	tenantID := "2c24a2a0-5223-47b7-a572-392aac75993a"
 
-	registry.AddDeployment(context.Background(), &lti_domain.BaseLTIDeployment{
+	err := registry.AddDeployment(context.Background(), &lti_domain.BaseLTIDeployment{
 		InternalID:    uuid.NewString(),
 		ForTenantID:   tenantID,
 		Issuer:        "https://" + lmsHost,
@@ -49,6 +49,9 @@ func main() {
 		TokenEndpoint: "https://" + lmsHost + "/token",
 		DeploymentID:  "demo-deployment",
 	})
+	if err != nil {
+		panic(err)
+	}
 
 	priv, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
@@ -78,17 +81,31 @@ func main() {
 		lti_launcher.WithDeepLinking(deepLinking),
 	)
 
-	ltiInstance := lti_http.NewServer(
-		lti_http.WithLauncher(launcher),
-		lti_http.WithVerifier(signVerifier),
-	)
-
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
 	http.DefaultClient = &http.Client{
 		Transport: tr,
 	}
+
+	// Visit https://<toolHost>/lti/register?openid_configuration=https://<lmsHost>/.well-known/openid-configuration
+	// to register the tool with the demo platform without any env vars.
+	registration := lti_registration.NewDynamicRegistration(
+		lti_registration.WithRegistry(registry),
+		lti_registration.WithClientName("go-lti demo tool"),
+		lti_registration.WithAllowedIssuers("https://"+lmsHost),
+		lti_registration.WithTenantResolver(func(r *http.Request, platform lti_domain.PlatformConfiguration) (string, error) {
+			return tenantID, nil
+		}),
+		lti_registration.WithHTTPClient(http.DefaultClient),
+		lti_registration.WithLogger(logger),
+	)
+
+	ltiInstance := lti_http.NewServer(
+		lti_http.WithLauncher(launcher),
+		lti_http.WithVerifier(signVerifier),
+		lti_http.WithDynamicRegistration(registration),
+	)
 	go func() {
 		log.Fatal(http.ListenAndServeTLS(
 			":9999",
@@ -0,0 +1,253 @@
+package ags
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/httplink"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.AGS = (*AGSService)(nil)
+
+type AGSService struct {
+	registry   lti_ports.Registry
+	tokens     lti_ports.ServiceTokenProvider
+	signer     lti_ports.AsymetricSigner
+	httpClient *http.Client
+	logger     lti_ports.Logger
+}
+
+func (s *AGSService) ListLineItems(ctx context.Context, session *lti_domain.LTIJWT, filter lti_domain.LineItemFilter) ([]lti_domain.LineItem, error) {
+	if session.AGS == nil || session.AGS.LineItems == "" {
+		return nil, lti_domain.ErrAGSNotAvailable
+	}
+	scope, err := readScope(session.AGS)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := url.Parse(session.AGS.LineItems)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lineitems url: %w", err)
+	}
+	q := next.Query()
+	if filter.ResourceLinkID != "" {
+		q.Set("resource_link_id", filter.ResourceLinkID)
+	}
+	if filter.ResourceID != "" {
+		q.Set("resource_id", filter.ResourceID)
+	}
+	if filter.Tag != "" {
+		q.Set("tag", filter.Tag)
+	}
+	if filter.Limit > 0 {
+		q.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	next.RawQuery = q.Encode()
+
+	items := []lti_domain.LineItem{}
+	pageURL := next.String()
+	for pageURL != "" {
+		var page []lti_domain.LineItem
+		resp, err := s.do(ctx, session, scope, http.MethodGet, pageURL, "", lti_domain.AGSMediaType_LineItemContainer, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		pageURL = httplink.Parse(resp.Header)["next"]
+	}
+
+	return items, nil
+}
+
+func (s *AGSService) GetLineItem(ctx context.Context, session *lti_domain.LTIJWT, lineItemURL string) (*lti_domain.LineItem, error) {
+	lineItemURL, err := resolveLineItem(session, lineItemURL)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := readScope(session.AGS)
+	if err != nil {
+		return nil, err
+	}
+
+	var item lti_domain.LineItem
+	if _, err := s.do(ctx, session, scope, http.MethodGet, lineItemURL, "", lti_domain.AGSMediaType_LineItem, nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *AGSService) CreateLineItem(ctx context.Context, session *lti_domain.LTIJWT, item lti_domain.LineItem) (*lti_domain.LineItem, error) {
+	if session.AGS == nil || session.AGS.LineItems == "" {
+		return nil, lti_domain.ErrAGSNotAvailable
+	}
+	if !session.AGS.HasScope(lti_domain.AGSScope_LineItem) {
+		return nil, lti_domain.ErrAGSScopeNotGranted
+	}
+
+	var created lti_domain.LineItem
+	if _, err := s.do(ctx, session, lti_domain.AGSScope_LineItem, http.MethodPost, session.AGS.LineItems, lti_domain.AGSMediaType_LineItem, lti_domain.AGSMediaType_LineItem, item, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (s *AGSService) UpdateLineItem(ctx context.Context, session *lti_domain.LTIJWT, item lti_domain.LineItem) (*lti_domain.LineItem, error) {
+	if item.ID == "" {
+		return nil, fmt.Errorf("line item id is required for update")
+	}
+	if session.AGS == nil {
+		return nil, lti_domain.ErrAGSNotAvailable
+	}
+	if !session.AGS.HasScope(lti_domain.AGSScope_LineItem) {
+		return nil, lti_domain.ErrAGSScopeNotGranted
+	}
+
+	var updated lti_domain.LineItem
+	if _, err := s.do(ctx, session, lti_domain.AGSScope_LineItem, http.MethodPut, item.ID, lti_domain.AGSMediaType_LineItem, lti_domain.AGSMediaType_LineItem, item, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (s *AGSService) DeleteLineItem(ctx context.Context, session *lti_domain.LTIJWT, lineItemURL string) error {
+	if lineItemURL == "" {
+		return fmt.Errorf("line item url is required for delete")
+	}
+	if session.AGS == nil {
+		return lti_domain.ErrAGSNotAvailable
+	}
+	if !session.AGS.HasScope(lti_domain.AGSScope_LineItem) {
+		return lti_domain.ErrAGSScopeNotGranted
+	}
+
+	_, err := s.do(ctx, session, lti_domain.AGSScope_LineItem, http.MethodDelete, lineItemURL, "", "", nil, nil)
+	return err
+}
+
+func (s *AGSService) PostScore(ctx context.Context, session *lti_domain.LTIJWT, lineItemURL string, score lti_domain.Score) error {
+	lineItemURL, err := resolveLineItem(session, lineItemURL)
+	if err != nil {
+		return err
+	}
+	if !session.AGS.HasScope(lti_domain.AGSScope_Score) {
+		return lti_domain.ErrAGSScopeNotGranted
+	}
+
+	if score.Timestamp == "" {
+		score.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+
+	scoresURL, err := url.Parse(lineItemURL)
+	if err != nil {
+		return fmt.Errorf("invalid line item url: %w", err)
+	}
+	scoresURL.Path = strings.TrimRight(scoresURL.Path, "/") + "/scores"
+
+	_, err = s.do(ctx, session, lti_domain.AGSScope_Score, http.MethodPost, scoresURL.String(), lti_domain.AGSMediaType_Score, "", score, nil)
+	return err
+}
+
+func resolveLineItem(session *lti_domain.LTIJWT, lineItemURL string) (string, error) {
+	if session.AGS == nil {
+		return "", lti_domain.ErrAGSNotAvailable
+	}
+	if lineItemURL != "" {
+		return lineItemURL, nil
+	}
+	if session.AGS.LineItem == "" {
+		return "", lti_domain.ErrAGSNotAvailable
+	}
+	return session.AGS.LineItem, nil
+}
+
+// readScope picks the granted scope that allows reading line items.
+func readScope(claim *lti_domain.LTIJWT_AGS) (string, error) {
+	if claim.HasScope(lti_domain.AGSScope_LineItem) {
+		return lti_domain.AGSScope_LineItem, nil
+	}
+	if claim.HasScope(lti_domain.AGSScope_LineItemReadOnly) {
+		return lti_domain.AGSScope_LineItemReadOnly, nil
+	}
+	return "", lti_domain.ErrAGSScopeNotGranted
+}
+
+// onPlatform reports whether target shares an origin with the session's AGS
+// endpoints, so the platform's token is never sent to another host.
+func onPlatform(claim *lti_domain.LTIJWT_AGS, target string) bool {
+	for _, endpoint := range []string{claim.LineItems, claim.LineItem} {
+		if endpoint != "" && httplink.SameOrigin(endpoint, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *AGSService) do(ctx context.Context, session *lti_domain.LTIJWT, scope, method, target, contentType, accept string, body any, out any) (*http.Response, error) {
+	if !onPlatform(session.AGS, target) {
+		s.logger.Error("refusing ags request off the platform origin", "method", method, "url", target)
+		return nil, lti_domain.ErrAGSForeignURL
+	}
+
+	dep, err := s.registry.GetDeployment(ctx, session.ClientID, session.Deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.tokens.AccessToken(ctx, dep, []string{scope})
+	if err != nil {
+		s.logger.Error("failed to obtain ags access token", "deployment", session.Deployment, "error", err)
+		return nil, err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		s.logger.Error("ags request failed", "method", method, "url", target, "status", resp.StatusCode)
+		return nil, fmt.Errorf("ags %s %s: unexpected status %d", method, target, resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("decode ags response: %w", err)
+		}
+	}
+
+	return resp, nil
+}
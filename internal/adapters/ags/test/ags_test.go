@@ -0,0 +1,252 @@
+package ags_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/ags"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/crypto"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+type fakePlatform struct {
+	server      *httptest.Server
+	tokenCalls  atomic.Int32
+	lastScore   lti_domain.Score
+	lastCreated lti_domain.LineItem
+}
+
+func newFakePlatform(t *testing.T, signer lti_ports.AsymetricSignerVerifier) *fakePlatform {
+	t.Helper()
+	p := &fakePlatform{}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		p.tokenCalls.Add(1)
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "bad form", http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("grant_type") != "client_credentials" {
+			http.Error(w, "bad grant", http.StatusBadRequest)
+			return
+		}
+		claims := jwt.MapClaims{}
+		if _, err := signer.Verify(r.FormValue("client_assertion"), &claims); err != nil {
+			http.Error(w, "bad assertion", http.StatusUnauthorized)
+			return
+		}
+		if claims["sub"] != "client1" {
+			http.Error(w, "bad sub", http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access-" + r.FormValue("scope"),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	})
+
+	requireBearer := func(w http.ResponseWriter, r *http.Request) bool {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer access-") {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return false
+		}
+		return true
+	}
+
+	mux.HandleFunc("GET /lineitems", func(w http.ResponseWriter, r *http.Request) {
+		if !requireBearer(w, r) {
+			return
+		}
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", `<`+p.server.URL+`/lineitems?page=2>; rel="next"`)
+			_ = json.NewEncoder(w).Encode([]lti_domain.LineItem{{ID: p.server.URL + "/lineitems/1", Label: "Quiz 1", ScoreMaximum: 10}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]lti_domain.LineItem{{ID: p.server.URL + "/lineitems/2", Label: "Quiz 2", ScoreMaximum: 20}})
+	})
+
+	mux.HandleFunc("POST /lineitems", func(w http.ResponseWriter, r *http.Request) {
+		if !requireBearer(w, r) {
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&p.lastCreated); err != nil {
+			http.Error(w, "bad body", http.StatusBadRequest)
+			return
+		}
+		created := p.lastCreated
+		created.ID = p.server.URL + "/lineitems/3"
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(created)
+	})
+
+	mux.HandleFunc("POST /lineitems/1/scores", func(w http.ResponseWriter, r *http.Request) {
+		if !requireBearer(w, r) {
+			return
+		}
+		if r.Header.Get("Content-Type") != lti_domain.AGSMediaType_Score {
+			http.Error(w, "bad content type", http.StatusUnsupportedMediaType)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&p.lastScore); err != nil {
+			http.Error(w, "bad body", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func setupAGS(t *testing.T) (lti_ports.AGS, *fakePlatform, *lti_domain.LTIJWT) {
+	t.Helper()
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	signer := crypto.NewRS256("tool-key", priv, &priv.PublicKey, "https://tool.example")
+
+	platform := newFakePlatform(t, signer)
+
+	reg := &lti_testadapters.FakeRegistry{}
+	reg.AddDeploymentQuick("client1", "dep1", platform.server.URL, platform.server.URL+"/jwks", "tenantA")
+
+	client := ags.NewAGS(ags.WithRegistry(reg), ags.WithSigner(signer))
+
+	session := &lti_domain.LTIJWT{
+		ClientID:   "client1",
+		Deployment: "dep1",
+		AGS: &lti_domain.LTIJWT_AGS{
+			LineItems: platform.server.URL + "/lineitems",
+			LineItem:  platform.server.URL + "/lineitems/1",
+			Scopes: []string{
+				lti_domain.AGSScope_LineItem,
+				lti_domain.AGSScope_Score,
+			},
+		},
+	}
+	return client, platform, session
+}
+
+func TestListLineItems_FollowsNextLink(t *testing.T) {
+	client, _, session := setupAGS(t)
+
+	items, err := client.ListLineItems(context.Background(), session, lti_domain.LineItemFilter{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 line items across pages, got %d", len(items))
+	}
+	if items[1].Label != "Quiz 2" {
+		t.Errorf("expected second page item %q, got %q", "Quiz 2", items[1].Label)
+	}
+}
+
+func TestCreateLineItem(t *testing.T) {
+	client, platform, session := setupAGS(t)
+
+	created, err := client.CreateLineItem(context.Background(), session, lti_domain.LineItem{Label: "Essay", ScoreMaximum: 100})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.HasSuffix(created.ID, "/lineitems/3") {
+		t.Errorf("expected created id to be returned, got %q", created.ID)
+	}
+	if platform.lastCreated.Label != "Essay" {
+		t.Errorf("expected platform to receive label %q, got %q", "Essay", platform.lastCreated.Label)
+	}
+}
+
+func TestPostScore_DefaultsToResourceLinkLineItem(t *testing.T) {
+	client, platform, session := setupAGS(t)
+
+	given := 8.5
+	max := 10.0
+	err := client.PostScore(context.Background(), session, "", lti_domain.Score{
+		UserID:           "user-1",
+		ScoreGiven:       &given,
+		ScoreMaximum:     &max,
+		ActivityProgress: lti_domain.ActivityProgress_Completed,
+		GradingProgress:  lti_domain.GradingProgress_FullyGraded,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if platform.lastScore.UserID != "user-1" {
+		t.Errorf("expected score for %q, got %q", "user-1", platform.lastScore.UserID)
+	}
+	if platform.lastScore.Timestamp == "" {
+		t.Errorf("expected timestamp to be filled in")
+	}
+}
+
+func TestPostScore_ScopeNotGranted(t *testing.T) {
+	client, platform, session := setupAGS(t)
+	session.AGS.Scopes = []string{lti_domain.AGSScope_LineItemReadOnly}
+
+	err := client.PostScore(context.Background(), session, "", lti_domain.Score{UserID: "user-1"})
+	if !errors.Is(err, lti_domain.ErrAGSScopeNotGranted) {
+		t.Fatalf("expected ErrAGSScopeNotGranted, got %v", err)
+	}
+	if platform.tokenCalls.Load() != 0 {
+		t.Errorf("expected no token request, got %d", platform.tokenCalls.Load())
+	}
+}
+
+func TestPostScore_NoAGSClaim(t *testing.T) {
+	client, _, session := setupAGS(t)
+	session.AGS = nil
+
+	err := client.PostScore(context.Background(), session, "", lti_domain.Score{UserID: "user-1"})
+	if !errors.Is(err, lti_domain.ErrAGSNotAvailable) {
+		t.Fatalf("expected ErrAGSNotAvailable, got %v", err)
+	}
+}
+
+func TestAGS_RejectsURLsOffPlatform(t *testing.T) {
+	const foreign = "https://attacker.example/lineitems/1"
+	tests := []struct {
+		name string
+		call func(lti_ports.AGS, *lti_domain.LTIJWT) error
+	}{
+		{"get", func(c lti_ports.AGS, s *lti_domain.LTIJWT) error {
+			_, err := c.GetLineItem(context.Background(), s, foreign)
+			return err
+		}},
+		{"update", func(c lti_ports.AGS, s *lti_domain.LTIJWT) error {
+			_, err := c.UpdateLineItem(context.Background(), s, lti_domain.LineItem{ID: foreign})
+			return err
+		}},
+		{"delete", func(c lti_ports.AGS, s *lti_domain.LTIJWT) error {
+			return c.DeleteLineItem(context.Background(), s, foreign)
+		}},
+		{"score", func(c lti_ports.AGS, s *lti_domain.LTIJWT) error {
+			return c.PostScore(context.Background(), s, foreign, lti_domain.Score{UserID: "user-1"})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, platform, session := setupAGS(t)
+
+			if err := tt.call(client, session); !errors.Is(err, lti_domain.ErrAGSForeignURL) {
+				t.Fatalf("expected ErrAGSForeignURL, got %v", err)
+			}
+			if platform.tokenCalls.Load() != 0 {
+				t.Errorf("expected no token request, got %d", platform.tokenCalls.Load())
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package clock
+
+import (
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// NewSystemClock returns a clock backed by time.Now. Adapters that accept a
+// clock option default to it.
+func NewSystemClock() lti_ports.Clock {
+	return systemClock{}
+}
@@ -0,0 +1,242 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/clock"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.AsymetricSignerVerifier = (*KeyRing)(nil)
+
+var (
+	ErrNoActiveKey = errors.New("key ring has no active signing key")
+	ErrUnknownKey  = errors.New("token signed with unknown or removed key")
+)
+
+// KeyRingKey is one key in a KeyRing and the window in which it is used.
+//
+//	before ActiveFrom     published and verifiable, not used for signing
+//	ActiveFrom..RetireAt  eligible for signing (newest ActiveFrom wins)
+//	RetireAt..RemoveAt    published and verifiable only, so live sessions survive
+//	after RemoveAt        gone
+//
+// Zero times mean "always" for ActiveFrom and "never" for RetireAt/RemoveAt.
+type KeyRingKey struct {
+	// KeyID must match the kid the signer writes into its tokens.
+	KeyID      string
+	Signer     lti_ports.AsymetricSignerVerifier
+	ActiveFrom time.Time
+	RetireAt   time.Time
+	RemoveAt   time.Time
+}
+
+func (k KeyRingKey) canSign(now time.Time) bool {
+	return !now.Before(k.ActiveFrom) && (k.RetireAt.IsZero() || now.Before(k.RetireAt)) && k.published(now)
+}
+
+func (k KeyRingKey) published(now time.Time) bool {
+	return k.RemoveAt.IsZero() || now.Before(k.RemoveAt)
+}
+
+// KeyRing composes several asymmetric signers so the tool key can be rotated
+// without invalidating sessions or deep link replies signed by the old key.
+type KeyRing struct {
+	mu    sync.RWMutex
+	keys  []KeyRingKey
+	clock lti_ports.Clock
+}
+
+type KeyRingOption func(*KeyRing)
+
+// WithKeyRingClock overrides the clock used to evaluate key windows.
+func WithKeyRingClock(clock lti_ports.Clock) KeyRingOption {
+	return func(k *KeyRing) {
+		k.clock = clock
+	}
+}
+
+// NewKeyRing creates a key ring from keys. At least one key is required.
+func NewKeyRing(keys []KeyRingKey, opts ...KeyRingOption) *KeyRing {
+	if len(keys) == 0 {
+		panic("a key ring requires at least one key")
+	}
+
+	k := &KeyRing{clock: clock.NewSystemClock()}
+	for _, opt := range opts {
+		opt(k)
+	}
+	for _, key := range keys {
+		if err := k.Add(key); err != nil {
+			panic(err)
+		}
+	}
+	return k
+}
+
+// Add places a new key on the ring, typically with a future ActiveFrom so
+// platforms can cache it before it starts signing.
+func (k *KeyRing) Add(key KeyRingKey) error {
+	if key.KeyID == "" || key.Signer == nil {
+		return fmt.Errorf("key ring entries need a key id and signer")
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, existing := range k.keys {
+		if existing.KeyID == key.KeyID {
+			return fmt.Errorf("key %q is already on the ring", key.KeyID)
+		}
+	}
+	k.keys = append(k.keys, key)
+	return nil
+}
+
+// Promote schedules keyID to start signing at the given time.
+func (k *KeyRing) Promote(keyID string, at time.Time) error {
+	return k.update(keyID, func(key *KeyRingKey) {
+		key.ActiveFrom = at
+	})
+}
+
+// Retire stops keyID from signing at retireAt and drops it from the published
+// set at removeAt. removeAt should be at least the longest token lifetime after
+// retireAt.
+func (k *KeyRing) Retire(keyID string, retireAt, removeAt time.Time) error {
+	if !removeAt.IsZero() && removeAt.Before(retireAt) {
+		return fmt.Errorf("removeAt must not be before retireAt")
+	}
+	return k.update(keyID, func(key *KeyRingKey) {
+		key.RetireAt = retireAt
+		key.RemoveAt = removeAt
+	})
+}
+
+func (k *KeyRing) update(keyID string, fn func(*KeyRingKey)) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for i := range k.keys {
+		if k.keys[i].KeyID == keyID {
+			fn(&k.keys[i])
+			return nil
+		}
+	}
+	return fmt.Errorf("key %q is not on the ring", keyID)
+}
+
+// ActiveKeyID returns the kid currently used for signing.
+func (k *KeyRing) ActiveKeyID() (string, error) {
+	key, err := k.active()
+	if err != nil {
+		return "", err
+	}
+	return key.KeyID, nil
+}
+
+func (k *KeyRing) active() (KeyRingKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := k.clock.Now()
+	var best *KeyRingKey
+	for i := range k.keys {
+		key := &k.keys[i]
+		if !key.canSign(now) {
+			continue
+		}
+		if best == nil || key.ActiveFrom.After(best.ActiveFrom) {
+			best = key
+		}
+	}
+	if best == nil {
+		return KeyRingKey{}, ErrNoActiveKey
+	}
+	return *best, nil
+}
+
+func (k *KeyRing) lookup(keyID string) (KeyRingKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := k.clock.Now()
+	for _, key := range k.keys {
+		if key.KeyID == keyID && key.published(now) {
+			return key, true
+		}
+	}
+	return KeyRingKey{}, false
+}
+
+func (k *KeyRing) GetIssuer() string {
+	if key, err := k.active(); err == nil {
+		return key.Signer.GetIssuer()
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.keys[0].Signer.GetIssuer()
+}
+
+// Sign signs with the active key.
+func (k *KeyRing) Sign(claims jwt.Claims, ttl time.Duration) (string, error) {
+	key, err := k.active()
+	if err != nil {
+		return "", err
+	}
+	return key.Signer.Sign(claims, ttl)
+}
+
+// Verify routes the token to the key named by its kid header. Tokens without
+// a kid are checked against the active key.
+func (k *KeyRing) Verify(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	unverified, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return nil, err
+	}
+
+	kid, _ := unverified.Header["kid"].(string)
+	if kid == "" {
+		key, err := k.active()
+		if err != nil {
+			return nil, err
+		}
+		return key.Signer.Verify(tokenString, claims)
+	}
+
+	key, ok := k.lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	return key.Signer.Verify(tokenString, claims)
+}
+
+// JWKs publishes every key that has not been removed, including keys that are
+// scheduled but not yet signing.
+func (k *KeyRing) JWKs(ctx context.Context) (*lti_domain.JWKS, error) {
+	k.mu.RLock()
+	now := k.clock.Now()
+	published := []KeyRingKey{}
+	for _, key := range k.keys {
+		if key.published(now) {
+			published = append(published, key)
+		}
+	}
+	k.mu.RUnlock()
+
+	out := &lti_domain.JWKS{Keys: []lti_domain.JWK{}}
+	for _, key := range published {
+		set, err := key.Signer.JWKs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("jwks for key %q: %w", key.KeyID, err)
+		}
+		out.Keys = append(out.Keys, set.Keys...)
+	}
+	return out, nil
+}
@@ -0,0 +1,121 @@
+package crypto_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/crypto"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+func newRSAKey(t *testing.T, kid string) lti_ports.AsymetricSignerVerifier {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.NewRS256(kid, priv, &priv.PublicKey, "https://tool.example")
+}
+
+func kidOf(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestKeyRing_SharedSuite(t *testing.T) {
+	ring := crypto.NewKeyRing([]crypto.KeyRingKey{
+		{KeyID: "old", Signer: newRSAKey(t, "old")},
+	})
+	runSignerVerifierTests(t, "KeyRing", ring)
+}
+
+func TestKeyRing_RotationWindows(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := lti_testadapters.NewFakeClock(now)
+
+	eccpriv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	next := crypto.NewES256("new", eccpriv, &eccpriv.PublicKey, "https://tool.example")
+
+	ring := crypto.NewKeyRing([]crypto.KeyRingKey{
+		{KeyID: "old", Signer: newRSAKey(t, "old")},
+		{KeyID: "new", Signer: next, ActiveFrom: now.Add(24 * time.Hour)},
+	}, crypto.WithKeyRingClock(clock))
+
+	// Before promotion: both keys published, old key signs.
+	jwks, err := ring.JWKs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("expected both keys to be published, got %d", len(jwks.Keys))
+	}
+	oldToken, err := ring.Sign(&jwt.RegisteredClaims{Subject: "u1"}, 48*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kidOf(t, oldToken) != "old" {
+		t.Fatalf("expected old key to sign before promotion, got %q", kidOf(t, oldToken))
+	}
+
+	// After promotion: new key signs, old token still verifies.
+	clock.Advance(25 * time.Hour)
+	if err := ring.Retire("old", clock.Now(), clock.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	newToken, err := ring.Sign(&jwt.RegisteredClaims{Subject: "u1"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kidOf(t, newToken) != "new" {
+		t.Fatalf("expected new key to sign after promotion, got %q", kidOf(t, newToken))
+	}
+	if _, err := ring.Verify(oldToken, &jwt.RegisteredClaims{}); err != nil {
+		t.Fatalf("expected retiring key to still verify, got %v", err)
+	}
+
+	// After removal: old key is unpublished and its tokens are rejected.
+	clock.Advance(2 * time.Hour)
+	if _, err := ring.Verify(oldToken, &jwt.RegisteredClaims{}); !errors.Is(err, crypto.ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey for removed key, got %v", err)
+	}
+	jwks, _ = ring.JWKs(context.Background())
+	if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != "new" {
+		t.Fatalf("expected only the new key to be published, got %+v", jwks.Keys)
+	}
+}
+
+func TestKeyRing_NoActiveKey(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ring := crypto.NewKeyRing([]crypto.KeyRingKey{
+		{KeyID: "future", Signer: newRSAKey(t, "future"), ActiveFrom: now.Add(time.Hour)},
+	}, crypto.WithKeyRingClock(lti_testadapters.NewFakeClock(now)))
+
+	if _, err := ring.Sign(&jwt.RegisteredClaims{}, time.Minute); !errors.Is(err, crypto.ErrNoActiveKey) {
+		t.Fatalf("expected ErrNoActiveKey, got %v", err)
+	}
+}
+
+func TestKeyRing_RejectsForeignKid(t *testing.T) {
+	ring := crypto.NewKeyRing([]crypto.KeyRingKey{
+		{KeyID: "a", Signer: newRSAKey(t, "a")},
+	})
+	foreign := newRSAKey(t, "b")
+
+	token, _ := foreign.Sign(&jwt.RegisteredClaims{}, time.Minute)
+	if _, err := ring.Verify(token, &jwt.RegisteredClaims{}); !errors.Is(err, crypto.ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
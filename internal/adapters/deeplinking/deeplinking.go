@@ -5,7 +5,6 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -104,7 +103,7 @@ func (d DeepLinkingService) HandleLaunch(w http.ResponseWriter, r *http.Request,
 		Targets:          targets,
 		AutoCreate:       autoCreate,
 		AcceptMediaTypes: mediaTypes,
-		AttachedKID:      attachedSession.ID,
+		AttachedKID:      attachedSession.SessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    d.signer.GetIssuer(),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -121,18 +120,11 @@ func (d DeepLinkingService) HandleLaunch(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	useSecureCookie := true
-	if os.Getenv("INSECURE_COOKIES") == "true" {
-		useSecureCookie = false
-	}
-	deepLinkContextCookie := &http.Cookie{
-		Name:     ContextKey_DeepLink,
-		Value:    deepLinkContextJWT,
-		Path:     "/lti/app/",
-		HttpOnly: true,
-		Secure:   useSecureCookie,
-		SameSite: http.SameSiteNoneMode,
-	}
+	deepLinkContextCookie := lti_domain.CookiePolicyFromContext(r.Context()).Cookie(
+		ContextKey_DeepLink,
+		deepLinkContextJWT,
+		lti_domain.RoutesFromContext(r.Context()).App()+"/",
+	)
 
 	http.SetCookie(w, deepLinkContextCookie)
 
@@ -0,0 +1,257 @@
+package deployment_admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_logger"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// deploymentAdminHTTP exposes registry management as a small JSON API:
+//
+//	GET    /deployments?tenant_id=&issuer=
+//	POST   /deployments
+//	GET    /deployments/{clientID}/{deploymentID}
+//	PATCH  /deployments/{clientID}/{deploymentID}
+//	DELETE /deployments/{clientID}/{deploymentID}
+//
+// Paths are relative; mount it with http.StripPrefix.
+type deploymentAdminHTTP struct {
+	registry  lti_ports.Registry
+	authorize lti_ports.AdminAuthorizer
+	logger    lti_ports.Logger
+	mux       *http.ServeMux
+}
+
+func NewDeploymentAdminHTTP(registry lti_ports.Registry, authorize lti_ports.AdminAuthorizer, logger lti_ports.Logger) http.Handler {
+	if registry == nil {
+		panic("a registry is required for the deployment admin handler")
+	}
+	if authorize == nil {
+		panic("an authorizer is required for the deployment admin handler")
+	}
+	if logger == nil {
+		logger = lti_logger.NewNoopLogger()
+	}
+
+	h := &deploymentAdminHTTP{
+		registry:  registry,
+		authorize: authorize,
+		logger:    logger,
+		mux:       http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /deployments", h.list)
+	h.mux.HandleFunc("POST /deployments", h.create)
+	h.mux.HandleFunc("GET /deployments/{clientID}/{deploymentID}", h.get)
+	h.mux.HandleFunc("PATCH /deployments/{clientID}/{deploymentID}", h.update)
+	h.mux.HandleFunc("DELETE /deployments/{clientID}/{deploymentID}", h.remove)
+
+	return h
+}
+
+func (h *deploymentAdminHTTP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(r) {
+		writeError(w, http.StatusForbidden, "forbidden")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+type deploymentDTO struct {
+	InternalID    string `json:"internal_id"`
+	TenantID      string `json:"tenant_id"`
+	Issuer        string `json:"issuer"`
+	ClientID      string `json:"client_id"`
+	DeploymentID  string `json:"deployment_id"`
+	JWKSURL       string `json:"jwks_url"`
+	AuthEndpoint  string `json:"auth_endpoint"`
+	TokenEndpoint string `json:"token_endpoint"`
+	Disabled      bool   `json:"disabled"`
+}
+
+func toDTO(dep lti_domain.Deployment) deploymentDTO {
+	dto := deploymentDTO{
+		InternalID:    dep.GetDeploymentID(),
+		Issuer:        dep.GetLTIIssuer(),
+		ClientID:      dep.GetLTIClientID(),
+		DeploymentID:  dep.GetLTIDeploymentID(),
+		JWKSURL:       dep.GetLTIJWKSURL(),
+		AuthEndpoint:  dep.GetLTIAuthEndpoint(),
+		TokenEndpoint: dep.GetLTITokenEndpoint(),
+		Disabled:      lti_domain.IsDeploymentDisabled(dep),
+	}
+	if tenant := dep.GetTenantID(); tenant != nil {
+		dto.TenantID = lti_domain.TenantIDString(tenant)
+	}
+	switch d := dep.(type) {
+	case lti_domain.BaseLTIDeployment:
+		dto.InternalID = d.InternalID
+	case *lti_domain.BaseLTIDeployment:
+		dto.InternalID = d.InternalID
+	}
+	return dto
+}
+
+func (d deploymentDTO) toDeployment() lti_domain.BaseLTIDeployment {
+	return lti_domain.BaseLTIDeployment{
+		InternalID:    d.InternalID,
+		ForTenantID:   d.TenantID,
+		Issuer:        d.Issuer,
+		ClientID:      d.ClientID,
+		JWKSURL:       d.JWKSURL,
+		AuthEndpoint:  d.AuthEndpoint,
+		TokenEndpoint: d.TokenEndpoint,
+		DeploymentID:  d.DeploymentID,
+		Disabled:      d.Disabled,
+	}
+}
+
+func (d deploymentDTO) validate() error {
+	switch {
+	case d.ClientID == "":
+		return errors.New("client_id is required")
+	case d.DeploymentID == "":
+		return errors.New("deployment_id is required")
+	case d.Issuer == "":
+		return errors.New("issuer is required")
+	case d.JWKSURL == "":
+		return errors.New("jwks_url is required")
+	case d.AuthEndpoint == "":
+		return errors.New("auth_endpoint is required")
+	}
+	return nil
+}
+
+func (h *deploymentAdminHTTP) list(w http.ResponseWriter, r *http.Request) {
+	filter := lti_domain.DeploymentFilter{Issuer: r.URL.Query().Get("issuer")}
+	if tenant := r.URL.Query().Get("tenant_id"); tenant != "" {
+		filter.TenantID = tenant
+	}
+
+	deps, err := h.registry.ListDeployments(r.Context(), filter)
+	if err != nil {
+		h.writeRegistryError(w, err)
+		return
+	}
+
+	out := make([]deploymentDTO, 0, len(deps))
+	for _, dep := range deps {
+		out = append(out, toDTO(dep))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (h *deploymentAdminHTTP) create(w http.ResponseWriter, r *http.Request) {
+	var dto deploymentDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json body")
+		return
+	}
+	if err := dto.validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// AddDeployment replaces, so check first to keep POST from clobbering a
+	// deployment that PATCH should be used for.
+	_, err := h.registry.GetDeployment(r.Context(), dto.ClientID, dto.DeploymentID)
+	switch {
+	case err == nil:
+		h.writeRegistryError(w, lti_domain.ErrDeploymentExists)
+		return
+	case !errors.Is(err, lti_domain.ErrDeploymentNotFound):
+		h.writeRegistryError(w, err)
+		return
+	}
+
+	if err := h.registry.AddDeployment(r.Context(), dto.toDeployment()); err != nil {
+		h.writeRegistryError(w, err)
+		return
+	}
+	h.logger.Info("deployment added", "clientID", dto.ClientID, "deploymentID", dto.DeploymentID)
+	writeJSON(w, http.StatusCreated, dto)
+}
+
+func (h *deploymentAdminHTTP) get(w http.ResponseWriter, r *http.Request) {
+	dep, err := h.registry.GetDeployment(r.Context(), r.PathValue("clientID"), r.PathValue("deploymentID"))
+	if err != nil {
+		h.writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toDTO(dep))
+}
+
+// update applies the fields present in the body on top of the stored
+// deployment, so {"disabled": true} or {"jwks_url": "..."} is enough. Only
+// BaseLTIDeployment can be patched; saving a custom type back as a base
+// deployment would drop its extra fields.
+func (h *deploymentAdminHTTP) update(w http.ResponseWriter, r *http.Request) {
+	clientID, deploymentID := r.PathValue("clientID"), r.PathValue("deploymentID")
+
+	dep, err := h.registry.GetDeployment(r.Context(), clientID, deploymentID)
+	if err != nil {
+		h.writeRegistryError(w, err)
+		return
+	}
+	switch dep.(type) {
+	case lti_domain.BaseLTIDeployment, *lti_domain.BaseLTIDeployment:
+	default:
+		writeError(w, http.StatusConflict, "deployment type cannot be patched")
+		return
+	}
+
+	dto := toDTO(dep)
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json body")
+		return
+	}
+	dto.ClientID, dto.DeploymentID = clientID, deploymentID
+	if err := dto.validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.registry.UpdateDeployment(r.Context(), dto.toDeployment()); err != nil {
+		h.writeRegistryError(w, err)
+		return
+	}
+	h.logger.Info("deployment updated", "clientID", clientID, "deploymentID", deploymentID, "disabled", dto.Disabled)
+	writeJSON(w, http.StatusOK, dto)
+}
+
+func (h *deploymentAdminHTTP) remove(w http.ResponseWriter, r *http.Request) {
+	clientID, deploymentID := r.PathValue("clientID"), r.PathValue("deploymentID")
+
+	if err := h.registry.RemoveDeployment(r.Context(), clientID, deploymentID); err != nil {
+		h.writeRegistryError(w, err)
+		return
+	}
+	h.logger.Info("deployment removed", "clientID", clientID, "deploymentID", deploymentID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *deploymentAdminHTTP) writeRegistryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, lti_domain.ErrDeploymentNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, lti_domain.ErrDeploymentExists):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		h.logger.Error("deployment admin registry error", "error", err)
+		writeError(w, http.StatusInternalServerError, "registry error")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
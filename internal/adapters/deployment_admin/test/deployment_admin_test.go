@@ -0,0 +1,144 @@
+package deployment_admin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/deployment_admin"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+func setupAdmin() (http.Handler, *lti_testadapters.FakeRegistry) {
+	reg := &lti_testadapters.FakeRegistry{}
+	reg.AddDeploymentQuick("client1", "dep1", "https://lms.example", "https://lms.example/jwks", "tenantA")
+	reg.AddDeploymentQuick("client1", "dep2", "https://other.example", "https://other.example/jwks", "tenantB")
+
+	authorize := func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer admin"
+	}
+	return deployment_admin.NewDeploymentAdminHTTP(reg, authorize, lti_testadapters.NewFakeLogger()), reg
+}
+
+func do(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer admin")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdmin_RejectsUnauthorized(t *testing.T) {
+	h, _ := setupAdmin()
+
+	req := httptest.NewRequest(http.MethodGet, "/deployments", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestAdmin_ListFiltersByTenant(t *testing.T) {
+	h, _ := setupAdmin()
+
+	w := do(h, http.MethodGet, "/deployments?tenant_id=tenantB", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var out []map[string]any
+	_ = json.NewDecoder(w.Body).Decode(&out)
+	if len(out) != 1 || out[0]["deployment_id"] != "dep2" {
+		t.Errorf("expected only dep2, got %v", out)
+	}
+}
+
+func TestAdmin_Create(t *testing.T) {
+	h, reg := setupAdmin()
+
+	body := `{"client_id":"client2","deployment_id":"dep3","issuer":"https://new.example","jwks_url":"https://new.example/jwks","auth_endpoint":"https://new.example/auth","tenant_id":"tenantC"}`
+	w := do(h, http.MethodPost, "/deployments", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if reg.MustGetDeploymentT(t, "dep3").GetTenantID() != "tenantC" {
+		t.Errorf("expected deployment to be stored with tenant")
+	}
+
+	w = do(h, http.MethodPost, "/deployments", body)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 on duplicate, got %d", w.Code)
+	}
+}
+
+func TestAdmin_CreateValidates(t *testing.T) {
+	h, _ := setupAdmin()
+
+	w := do(h, http.MethodPost, "/deployments", `{"client_id":"client2"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestAdmin_PatchRotatesJWKSAndDisables(t *testing.T) {
+	h, reg := setupAdmin()
+
+	w := do(h, http.MethodPatch, "/deployments/client1/dep1", `{"jwks_url":"https://lms.example/jwks-v2","disabled":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	dep := reg.MustGetDeploymentT(t, "dep1")
+	if dep.GetLTIJWKSURL() != "https://lms.example/jwks-v2" {
+		t.Errorf("expected rotated jwks url, got %q", dep.GetLTIJWKSURL())
+	}
+	if !lti_domain.IsDeploymentDisabled(dep) {
+		t.Errorf("expected deployment to be disabled")
+	}
+	if dep.GetLTIIssuer() != "https://lms.example" {
+		t.Errorf("expected untouched fields to be preserved, got issuer %q", dep.GetLTIIssuer())
+	}
+}
+
+type planDeployment struct {
+	lti_domain.BaseLTIDeployment
+	Plan string
+}
+
+func TestAdmin_PatchRefusesCustomDeployment(t *testing.T) {
+	h, reg := setupAdmin()
+	base := reg.MustGetDeploymentT(t, "dep1").(lti_domain.BaseLTIDeployment)
+	reg.Deployments.Store("dep1", planDeployment{BaseLTIDeployment: base, Plan: "pro"})
+
+	w := do(h, http.MethodPatch, "/deployments/client1/dep1", `{"disabled":true}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", w.Code, w.Body.String())
+	}
+
+	dep, ok := reg.MustGetDeploymentT(t, "dep1").(planDeployment)
+	if !ok || dep.Plan != "pro" {
+		t.Errorf("expected the custom deployment to be left as is, got %#v", dep)
+	}
+}
+
+func TestAdmin_DeleteAndNotFound(t *testing.T) {
+	h, reg := setupAdmin()
+
+	w := do(h, http.MethodDelete, "/deployments/client1/dep2", "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	if reg.CountDeployments() != 1 {
+		t.Errorf("expected 1 deployment left, got %d", reg.CountDeployments())
+	}
+
+	w = do(h, http.MethodGet, "/deployments/client1/dep2", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
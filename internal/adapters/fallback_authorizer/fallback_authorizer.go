@@ -9,13 +9,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"os"
+	"net/url"
 	"regexp"
 	"slices"
 	"time"
 
 	pages "github.com/vizdos-enterprises/go-lti/internal/adapters/fallback_authorizer/frontend"
 	"github.com/vizdos-enterprises/go-lti/internal/adapters/observability"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/session"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
 )
@@ -25,14 +26,16 @@ type pkceAuthorizer struct {
 	signer    lti_ports.Signer
 	logger    lti_ports.Logger
 	telemetry lti_ports.TelemetryPort
+	policy    lti_domain.Policy
 }
 
-func New(store lti_ports.EphemeralStore, signer lti_ports.Signer, logger lti_ports.Logger, telemetry lti_ports.TelemetryPort) *pkceAuthorizer {
-	return &pkceAuthorizer{ephemeral: store, signer: signer, logger: logger, telemetry: telemetry}
+func New(store lti_ports.EphemeralStore, signer lti_ports.Signer, logger lti_ports.Logger, telemetry lti_ports.TelemetryPort, policy lti_domain.Policy) *pkceAuthorizer {
+	return &pkceAuthorizer{ephemeral: store, signer: signer, logger: logger, telemetry: telemetry, policy: policy}
 }
 
 func (p *pkceAuthorizer) HandleFallback(w http.ResponseWriter, r *http.Request, exchangeToken string) {
-	joined := fmt.Sprintf("/lti/auth/verify?exchange=%s", exchangeToken)
+	auth := lti_domain.RoutesFromContext(r.Context()).Auth()
+	joined := fmt.Sprintf("%s/verify?exchange=%s", auth, url.QueryEscape(exchangeToken))
 	http.Redirect(w, r, joined, http.StatusFound)
 }
 
@@ -187,7 +190,7 @@ func (p *pkceAuthorizer) exchangeForToken(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	signed, err := p.signer.Sign(exchangeInfo.Data.Claims, time.Hour)
+	signed, _, err := session.Sign(p.signer, exchangeInfo.Data.Claims, p.policy, time.Now())
 	if err != nil {
 		observability.CaptureRequestError(r, err, "failed to sign internal jwt")
 		p.logger.Error("failed to sign internal jwt", p.withContext(r, "error", err, "code", ErrFailedToSign)...)
@@ -195,10 +198,6 @@ func (p *pkceAuthorizer) exchangeForToken(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	useSecureCookie := true
-	if os.Getenv("INSECURE_COOKIES") == "true" {
-		useSecureCookie = false
-	}
 	p.telemetry.EmitLaunch(lti_domain.LaunchEvent{
 		At:          time.Now().UTC(),
 		Method:      lti_domain.LaunchMethodPKCE,
@@ -209,15 +208,7 @@ func (p *pkceAuthorizer) exchangeForToken(w http.ResponseWriter, r *http.Request
 		UserID:      exchangeInfo.Data.Claims.UserInfo.UserID,
 		Impostering: exchangeInfo.Data.Claims.Impostering,
 	})
-	cookie := &http.Cookie{
-		Name:     lti_domain.ContextKey_Session,
-		Value:    signed,
-		Path:     exchangeInfo.Data.To,
-		HttpOnly: true,
-		Secure:   useSecureCookie,
-		SameSite: http.SameSiteNoneMode,
-	}
-	http.SetCookie(w, cookie)
+	session.SetCookies(w, r, signed, lti_domain.RoutesFromContext(r.Context()).App()+"/")
 
 	w.Header().Add("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]string{
@@ -15,3 +15,6 @@ var ErrorHTML []byte
 
 //go:embed style.css
 var Styles []byte
+
+//go:embed storage_access.html
+var StorageAccessHTML []byte
@@ -21,7 +21,7 @@ func setupAuthorizer() (*pkceAuthorizer, *lti_testadapters.FakeRegistry, *lti_te
 	signer := &lti_testadapters.FakeSigner{ReturnSignedValue: "signed.jwt"}
 	logger := lti_testadapters.NewFakeLogger()
 
-	p := New(reg, signer, logger, telemetry.NoopTelemetry{})
+	p := New(reg, signer, logger, telemetry.NoopTelemetry{}, lti_domain.DefaultPolicy())
 	return p, reg, signer, logger
 }
 
@@ -454,8 +454,11 @@ func TestExchangeForToken_Success(t *testing.T) {
 	}
 
 	cookies := resp.Cookies()
-	if len(cookies) != 1 {
-		t.Fatalf("expected 1 cookie, got %d", len(cookies))
+	if len(cookies) != 2 {
+		t.Fatalf("expected session and refresh cookies, got %d", len(cookies))
+	}
+	if refresh := cookies[1]; refresh.Name != lti_domain.ContextKey_SessionRefresh || refresh.Path != "/lti/auth/" || refresh.Value != "signed.jwt" {
+		t.Fatalf("unexpected refresh cookie %+v", refresh)
 	}
 
 	cookie := cookies[0]
@@ -465,8 +468,8 @@ func TestExchangeForToken_Success(t *testing.T) {
 	if cookie.Value != "signed.jwt" {
 		t.Fatalf("expected cookie value %q, got %q", "signed.jwt", cookie.Value)
 	}
-	if cookie.Path != "/lti/app/finalize" {
-		t.Fatalf("expected cookie path %q, got %q", "/lti/app/finalize", cookie.Path)
+	if cookie.Path != "/lti/app/" {
+		t.Fatalf("expected cookie scoped to the app routes, got %q", cookie.Path)
 	}
 	if !cookie.HttpOnly {
 		t.Fatal("expected cookie to be HttpOnly")
@@ -0,0 +1,93 @@
+package fallback_authorizer
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/platform_storage"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.PlatformStorageAuthorizer = (*platformStorageAuthorizer)(nil)
+
+const (
+	storageStateField  = "lti_storage_state"
+	storageNonceField  = "lti_storage_nonce"
+	storageLookupField = "lti_storage_lookup"
+)
+
+// platformStorageAuthorizer implements the LTI Client Side postMessages spec.
+// Launches the platform vouches for skip the cookie check; everything else
+// is handed to next.
+type platformStorageAuthorizer struct {
+	next   lti_ports.FallbackAuthorizer
+	logger lti_ports.Logger
+}
+
+func NewPlatformStorage(next lti_ports.FallbackAuthorizer, logger lti_ports.Logger) *platformStorageAuthorizer {
+	if next == nil {
+		panic("platform storage authorizer requires a next fallback authorizer")
+	}
+	return &platformStorageAuthorizer{next: next, logger: logger}
+}
+
+func (p *platformStorageAuthorizer) HandleFallback(w http.ResponseWriter, r *http.Request, exchangeToken string) {
+	p.next.HandleFallback(w, r, exchangeToken)
+}
+
+func (p *platformStorageAuthorizer) Route() *http.ServeMux {
+	return p.next.Route()
+}
+
+func stateKey(state string) string {
+	return "state_" + state
+}
+
+func nonceKey(nonce string) string {
+	return "nonce_" + nonce
+}
+
+func (p *platformStorageAuthorizer) StoreLogin(w http.ResponseWriter, r *http.Request, login lti_domain.StorageLogin, redirect string) error {
+	items := []platform_storage.Item{
+		platform_storage.NewItem(stateKey(login.State), login.State),
+		platform_storage.NewItem(nonceKey(login.Nonce), login.Nonce),
+	}
+	for key, value := range login.Extra {
+		items = append(items, platform_storage.NewItem(key, value))
+	}
+	return platform_storage.PutAndRedirect(w, login.Target, login.Origin, items, redirect)
+}
+
+func (p *platformStorageAuthorizer) VerifyLaunch(w http.ResponseWriter, r *http.Request, login lti_domain.StorageLogin) lti_domain.StorageCheck {
+	if r.PostFormValue(storageLookupField) == "" {
+		// Carry every posted field over so earlier lookups aren't repeated.
+		fields := map[string]string{storageLookupField: "1"}
+		for name := range r.PostForm {
+			fields[name] = r.PostFormValue(name)
+		}
+
+		lookups := []platform_storage.Lookup{
+			platform_storage.NewLookup(stateKey(login.State), storageStateField),
+			platform_storage.NewLookup(nonceKey(login.Nonce), storageNonceField),
+		}
+		if err := platform_storage.GetAndPost(w, login.Target, login.Origin, r.URL.Path, fields, lookups); err != nil {
+			p.logger.Error("failed to render platform storage lookup", "error", err)
+		}
+		return lti_domain.StorageCheckPending
+	}
+
+	state := r.PostFormValue(storageStateField)
+	nonce := r.PostFormValue(storageNonceField)
+	if state == "" && nonce == "" {
+		return lti_domain.StorageCheckUnavailable
+	}
+
+	if subtle.ConstantTimeCompare([]byte(state), []byte(login.State)) != 1 ||
+		subtle.ConstantTimeCompare([]byte(nonce), []byte(login.Nonce)) != 1 {
+		p.logger.Warn("platform storage returned a different login")
+		return lti_domain.StorageCheckMismatch
+	}
+
+	return lti_domain.StorageCheckVerified
+}
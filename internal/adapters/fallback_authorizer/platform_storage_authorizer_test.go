@@ -0,0 +1,93 @@
+package fallback_authorizer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+func storageLogin() lti_domain.StorageLogin {
+	return lti_domain.StorageLogin{
+		Target: "_parent",
+		Origin: "https://lms.example",
+		State:  "state-1",
+		Nonce:  "nonce-1",
+	}
+}
+
+func launchRequest(form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/lti/1.3/launch", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_ = req.ParseForm()
+	return req
+}
+
+func TestPlatformStorage_StoreLogin(t *testing.T) {
+	p := NewPlatformStorage(&lti_testadapters.FakeFallbackAuthorizer{}, lti_testadapters.NewFakeLogger())
+
+	login := storageLogin()
+	login.Extra = map[string]string{"extra_key": "extra"}
+
+	w := httptest.NewRecorder()
+	if err := p.StoreLogin(w, httptest.NewRequest(http.MethodPost, "/oidc", nil), login, "https://lms.example/authorize?state=state-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{"lti.put_data", "state_state-1", "nonce_nonce-1", "extra_key", "https://lms.example"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected page to contain %q", want)
+		}
+	}
+}
+
+func TestPlatformStorage_VerifyLaunch(t *testing.T) {
+	tests := []struct {
+		name string
+		form url.Values
+		want lti_domain.StorageCheck
+	}{
+		{"first visit", url.Values{"id_token": {"tok"}}, lti_domain.StorageCheckPending},
+		{"verified", url.Values{storageLookupField: {"1"}, storageStateField: {"state-1"}, storageNonceField: {"nonce-1"}}, lti_domain.StorageCheckVerified},
+		{"no answer", url.Values{storageLookupField: {"1"}}, lti_domain.StorageCheckUnavailable},
+		{"wrong state", url.Values{storageLookupField: {"1"}, storageStateField: {"other"}, storageNonceField: {"nonce-1"}}, lti_domain.StorageCheckMismatch},
+		{"wrong nonce", url.Values{storageLookupField: {"1"}, storageStateField: {"state-1"}, storageNonceField: {"other"}}, lti_domain.StorageCheckMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlatformStorage(&lti_testadapters.FakeFallbackAuthorizer{}, lti_testadapters.NewFakeLogger())
+
+			w := httptest.NewRecorder()
+			got := p.VerifyLaunch(w, launchRequest(tt.form), storageLogin())
+			if got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+			if got == lti_domain.StorageCheckPending {
+				body := w.Body.String()
+				if !strings.Contains(body, "lti.get_data") || !strings.Contains(body, `name="id_token"`) {
+					t.Fatalf("expected lookup page carrying the posted fields")
+				}
+			} else if w.Body.Len() != 0 {
+				t.Fatalf("expected nothing written, got %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPlatformStorage_DelegatesFallback(t *testing.T) {
+	next := &lti_testadapters.FakeFallbackAuthorizer{}
+	p := NewPlatformStorage(next, lti_testadapters.NewFakeLogger())
+
+	p.HandleFallback(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/swap", nil), "exchange-1")
+
+	next.MustHaveBeenCalled(t)
+	if next.FallbackToken != "exchange-1" {
+		t.Fatalf("expected exchange token to be passed on, got %q", next.FallbackToken)
+	}
+}
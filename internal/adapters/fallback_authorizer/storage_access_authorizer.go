@@ -0,0 +1,100 @@
+package fallback_authorizer
+
+import (
+	"crypto/rand"
+	"net/http"
+	"net/url"
+	"time"
+
+	pages "github.com/vizdos-enterprises/go-lti/internal/adapters/fallback_authorizer/frontend"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.FallbackAuthorizer = (*storageAccessAuthorizer)(nil)
+
+// storageAccessRetried marks a swap that already went through the Storage
+// Access API, so a second missing cookie goes straight to next.
+const storageAccessRetried = "storage_access"
+
+// storageAccessAuthorizer asks the browser for cookie access with the Storage
+// Access API and retries the swap. Browsers that deny access, or don't
+// support the API, are handed to next.
+type storageAccessAuthorizer struct {
+	next      lti_ports.FallbackAuthorizer
+	ephemeral lti_ports.EphemeralStore
+	logger    lti_ports.Logger
+	swapTTL   time.Duration
+}
+
+func NewStorageAccess(next lti_ports.FallbackAuthorizer, store lti_ports.EphemeralStore, logger lti_ports.Logger, swapTTL time.Duration) *storageAccessAuthorizer {
+	if next == nil {
+		panic("storage access authorizer requires a next fallback authorizer")
+	}
+	return &storageAccessAuthorizer{next: next, ephemeral: store, logger: logger, swapTTL: swapTTL}
+}
+
+func (s *storageAccessAuthorizer) HandleFallback(w http.ResponseWriter, r *http.Request, exchangeToken string) {
+	if r.URL.Query().Get(storageAccessRetried) != "" {
+		s.logger.Warn("cookies still blocked after storage access was granted")
+		s.next.HandleFallback(w, r, exchangeToken)
+		return
+	}
+
+	auth := lti_domain.RoutesFromContext(r.Context()).Auth()
+	http.Redirect(w, r, auth+"/storage-access?exchange="+url.QueryEscape(exchangeToken), http.StatusFound)
+}
+
+func (s *storageAccessAuthorizer) Route() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/storage-access", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "text/html")
+		w.Write(pages.StorageAccessHTML)
+	})
+	mux.HandleFunc("/storage-access/retry", s.retrySwap)
+	mux.HandleFunc("/storage-access/fallback", func(w http.ResponseWriter, r *http.Request) {
+		s.next.HandleFallback(w, r, r.URL.Query().Get("exchange"))
+	})
+
+	mux.Handle("/", s.next.Route())
+	return mux
+}
+
+// retrySwap turns the exchange token back into a swap code and sends the
+// browser through the swap again, now that it may keep cookies.
+func (s *storageAccessAuthorizer) retrySwap(w http.ResponseWriter, r *http.Request) {
+	routes := lti_domain.RoutesFromContext(r.Context())
+
+	exchangeToken := r.URL.Query().Get("exchange")
+	if exchangeToken == "" {
+		http.Redirect(w, r, routes.ErrorURL("missing token"), http.StatusFound)
+		return
+	}
+
+	info, err := s.ephemeral.GetAndDeleteExchangeToken(r.Context(), exchangeToken)
+	if err != nil || info.Exchanged || info.Data == nil || time.Now().UTC().After(info.ClaimableUntil) {
+		s.logger.Warn("storage access retry with an unusable exchange token", "error", err)
+		http.Redirect(w, r, routes.ErrorURL("invalid token"), http.StatusFound)
+		return
+	}
+
+	swap := *info.Data
+	swap.StorageAccess = true
+
+	code := rand.Text()
+	if err := s.ephemeral.SaveSwapToken(r.Context(), code, swap, s.swapTTL); err != nil {
+		s.logger.Error("failed to save swap token", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	swapPath := routes.Versioned(lti_domain.LTIVersionFromContext(r.Context()), "swap")
+	next := url.Values{}
+	next.Set("code", code)
+	next.Set(storageAccessRetried, "1")
+
+	cookie := lti_domain.CookiePolicyFromContext(r.Context()).Cookie(lti_domain.ContextKey_CookieConfirmation, code, swapPath)
+	http.SetCookie(w, cookie)
+	http.Redirect(w, r, swapPath+"?"+next.Encode(), http.StatusFound)
+}
@@ -0,0 +1,156 @@
+package fallback_authorizer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+func setupStorageAccess() (*storageAccessAuthorizer, *lti_testadapters.FakeFallbackAuthorizer, *lti_testadapters.FakeRegistry) {
+	next := &lti_testadapters.FakeFallbackAuthorizer{}
+	reg := &lti_testadapters.FakeRegistry{}
+	return NewStorageAccess(next, reg, lti_testadapters.NewFakeLogger(), 30*time.Second), next, reg
+}
+
+func saveExchange(t *testing.T, reg *lti_testadapters.FakeRegistry, id string, ex lti_domain.ExchangeToken) {
+	t.Helper()
+	if err := reg.SaveExchangeToken(context.Background(), id, ex, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStorageAccess_HandleFallbackServesInterstitial(t *testing.T) {
+	s, next, _ := setupStorageAccess()
+
+	w := httptest.NewRecorder()
+	s.HandleFallback(w, httptest.NewRequest(http.MethodGet, "/lti/1.3/swap?code=abc", nil), "exchange-1")
+
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/lti/auth/storage-access?exchange=exchange-1" {
+		t.Fatalf("expected redirect to the storage access page, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+	if next.FallbackCalled {
+		t.Fatalf("expected PKCE not to be used yet")
+	}
+}
+
+func TestStorageAccess_HandleFallbackAfterRetryUsesNext(t *testing.T) {
+	s, next, _ := setupStorageAccess()
+
+	s.HandleFallback(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/lti/1.3/swap?code=abc&storage_access=1", nil), "exchange-1")
+
+	next.MustHaveBeenCalled(t)
+}
+
+func TestStorageAccess_Page(t *testing.T) {
+	s, _, _ := setupStorageAccess()
+
+	w := httptest.NewRecorder()
+	s.Route().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/storage-access?exchange=exchange-1", nil))
+
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(body, "requestStorageAccess") || !strings.Contains(body, "hasStorageAccess") {
+		t.Fatalf("expected storage access page, got %d", w.Code)
+	}
+}
+
+func TestStorageAccess_RetrySwapUsesConfiguredVersion(t *testing.T) {
+	s, _, reg := setupStorageAccess()
+	saveExchange(t, reg, "exchange-1", lti_domain.ExchangeToken{
+		Data:           &lti_domain.SwapToken{To: "/lti/app/"},
+		ClaimableUntil: time.Now().UTC().Add(time.Minute),
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/storage-access/retry?exchange=exchange-1", nil)
+	req = req.WithContext(lti_domain.ContextWithLTIVersion(req.Context(), "2.0"))
+	w := httptest.NewRecorder()
+	s.Route().ServeHTTP(w, req)
+
+	loc, err := url.Parse(w.Header().Get("Location"))
+	if err != nil || loc.Path != "/lti/2.0/swap" {
+		t.Fatalf("expected the swap for the configured version, got %q", w.Header().Get("Location"))
+	}
+}
+
+func TestStorageAccess_DeniedFallsBackToNext(t *testing.T) {
+	s, next, _ := setupStorageAccess()
+
+	s.Route().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/storage-access/fallback?exchange=exchange-1", nil))
+
+	next.MustHaveBeenCalled(t)
+	if next.FallbackToken != "exchange-1" {
+		t.Fatalf("expected exchange token to be passed on, got %q", next.FallbackToken)
+	}
+}
+
+func TestStorageAccess_RetrySwap(t *testing.T) {
+	s, _, reg := setupStorageAccess()
+	saveExchange(t, reg, "exchange-1", lti_domain.ExchangeToken{
+		Data:           &lti_domain.SwapToken{To: "/lti/app/", RequestorUA: "ua"},
+		ClaimableUntil: time.Now().UTC().Add(time.Minute),
+	})
+
+	w := httptest.NewRecorder()
+	s.Route().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/storage-access/retry?exchange=exchange-1", nil))
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected redirect to the swap, got %d", w.Code)
+	}
+	loc, err := url.Parse(w.Header().Get("Location"))
+	if err != nil || loc.Path != "/lti/1.3/swap" || loc.Query().Get(storageAccessRetried) == "" {
+		t.Fatalf("unexpected swap redirect %q", w.Header().Get("Location"))
+	}
+
+	code := loc.Query().Get("code")
+	swap, err := reg.GetAndDeleteSwapToken(context.Background(), code)
+	if err != nil {
+		t.Fatalf("expected a new swap token: %v", err)
+	}
+	if !swap.StorageAccess || swap.To != "/lti/app/" || swap.RequestorUA != "ua" {
+		t.Fatalf("unexpected swap token %+v", swap)
+	}
+	if reg.GetLastSwapTokenTTL() != 30*time.Second {
+		t.Fatalf("expected swap TTL to be used, got %s", reg.GetLastSwapTokenTTL())
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != lti_domain.ContextKey_CookieConfirmation || cookies[0].Value != code {
+		t.Fatalf("expected confirmation cookie for the new code, got %+v", cookies)
+	}
+}
+
+func TestStorageAccess_RetryRejectsUnusableExchange(t *testing.T) {
+	tests := []struct {
+		name string
+		ex   *lti_domain.ExchangeToken
+	}{
+		{"unknown", nil},
+		{"claimed by PKCE", &lti_domain.ExchangeToken{Data: &lti_domain.SwapToken{}, ClaimableUntil: time.Now().Add(time.Minute), Exchanged: true}},
+		{"expired", &lti_domain.ExchangeToken{Data: &lti_domain.SwapToken{}, ClaimableUntil: time.Now().Add(-time.Minute)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, reg := setupStorageAccess()
+			if tt.ex != nil {
+				saveExchange(t, reg, "exchange-1", *tt.ex)
+			}
+
+			w := httptest.NewRecorder()
+			s.Route().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/storage-access/retry?exchange=exchange-1", nil))
+
+			if w.Code != http.StatusFound || !strings.HasPrefix(w.Header().Get("Location"), "/lti/auth/error?") {
+				t.Fatalf("expected error page, got %d %q", w.Code, w.Header().Get("Location"))
+			}
+			if len(w.Result().Cookies()) != 0 {
+				t.Fatalf("expected no confirmation cookie")
+			}
+		})
+	}
+}
@@ -6,3 +6,9 @@ import (
 
 //go:embed session.js
 var SessionInitJS []byte
+
+//go:embed token_bootstrap.html
+var TokenBootstrapHTML []byte
+
+//go:embed session_refresh.js
+var SessionRefreshJS []byte
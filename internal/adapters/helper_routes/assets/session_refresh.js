@@ -0,0 +1,41 @@
+(function () {
+    const endpoint = {{.EndpointJSON}};
+    // Refresh a minute before the session runs out.
+    const margin = 60 * 1000;
+    let timer = null;
+
+    function schedule(expiresAt) {
+        clearTimeout(timer);
+        const delay = Math.max(expiresAt * 1000 - Date.now() - margin, 5000);
+        timer = setTimeout(refresh, delay);
+    }
+
+    async function refresh() {
+        let res;
+        try {
+            res = await fetch(endpoint, {
+                method: "POST",
+                credentials: "include",
+                headers: { "X-Requested-With": "XMLHttpRequest" },
+            });
+        } catch (e) {
+            // Network trouble; try again shortly.
+            timer = setTimeout(refresh, 30 * 1000);
+            return;
+        }
+
+        if (!res.ok) {
+            window.dispatchEvent(new CustomEvent("lti:session-expired"));
+            return;
+        }
+
+        const body = await res.json();
+        window.dispatchEvent(
+            new CustomEvent("lti:session-refreshed", { detail: body }),
+        );
+        schedule(body.expires_at);
+    }
+
+    window.ltiRefreshSession = refresh;
+    schedule({{.ExpiresAt}});
+})();
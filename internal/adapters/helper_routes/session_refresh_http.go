@@ -0,0 +1,52 @@
+package helper_routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"text/template"
+
+	helper_routes_assets "github.com/vizdos-enterprises/go-lti/internal/adapters/helper_routes/assets"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+type sessionRefreshHTTP struct {
+	tpl *template.Template
+}
+
+// NewSessionRefreshHTTP serves a script that calls the refresh endpoint
+// shortly before the current session expires, and again after every refresh.
+// The script is plain JavaScript, so values are JSON-encoded into it rather
+// than escaped by html/template.
+func NewSessionRefreshHTTP() *sessionRefreshHTTP {
+	return &sessionRefreshHTTP{
+		tpl: template.Must(template.New("session_refresh.js").Parse(string(helper_routes_assets.SessionRefreshJS))),
+	}
+}
+
+func (s *sessionRefreshHTTP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	session, ok := lti_domain.LTIFromContext(r.Context())
+	if !ok || session.ExpiresAt == nil {
+		http.Error(w, "Invalid LTI session", http.StatusUnauthorized)
+		return
+	}
+
+	endpoint, err := json.Marshal(lti_domain.RoutesFromContext(r.Context()).Refresh())
+	if err != nil {
+		http.Error(w, "Failed to render session refresh", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/javascript; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	err = s.tpl.Execute(w, struct {
+		EndpointJSON string
+		ExpiresAt    int64
+	}{
+		EndpointJSON: string(endpoint),
+		ExpiresAt:    session.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		http.Error(w, "Failed to render session refresh", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,73 @@
+package helper_routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/helper_routes"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+func TestSessionRefreshScript(t *testing.T) {
+	expires := time.Now().Add(time.Hour)
+	ctx := lti_domain.ContextWithLTI(t.Context(), &lti_domain.LTIJWT{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expires),
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/session-refresh.js", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+	helper_routes.NewSessionRefreshHTTP().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("expected no-store, got %q", got)
+	}
+
+	body := rr.Body.String()
+	if !strings.Contains(body, `"/lti/auth/refresh"`) {
+		t.Errorf("expected refresh endpoint in script, got:\n%s", body)
+	}
+	if !strings.Contains(body, strconv.FormatInt(expires.Unix(), 10)) {
+		t.Errorf("expected session expiry in script, got:\n%s", body)
+	}
+}
+
+func TestSessionRefreshScript_EncodesEndpointAsJS(t *testing.T) {
+	ctx := lti_domain.ContextWithLTI(t.Context(), &lti_domain.LTIJWT{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	ctx = lti_domain.ContextWithRoutes(ctx, lti_domain.Routes{Prefix: `/a&b"c`}.Normalize())
+
+	req := httptest.NewRequest(http.MethodGet, "/session-refresh.js", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+	helper_routes.NewSessionRefreshHTTP().ServeHTTP(rr, req)
+
+	body := rr.Body.String()
+	if !strings.Contains(body, `const endpoint = "/a\u0026b\"c/auth/refresh";`) {
+		t.Errorf("expected a JS string literal for the endpoint, got:\n%s", body)
+	}
+	if strings.Contains(body, "&amp;") || strings.Contains(body, "&#34;") {
+		t.Errorf("expected no HTML escaping in the script, got:\n%s", body)
+	}
+}
+
+func TestSessionRefreshScript_NoSession(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/session-refresh.js", nil)
+	rr := httptest.NewRecorder()
+	helper_routes.NewSessionRefreshHTTP().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rr.Code)
+	}
+}
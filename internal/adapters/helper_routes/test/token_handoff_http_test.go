@@ -0,0 +1,74 @@
+package helper_routes_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/helper_routes"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+func handoffRequest(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/handoff", nil)
+	if token != "" {
+		req = req.WithContext(context.WithValue(req.Context(), lti_domain.ContextKey_RawSession, token))
+	}
+	return req
+}
+
+func TestTokenHandoff_Fragment(t *testing.T) {
+	h := helper_routes.NewTokenHandoffHTTP("https://spa.example/app?x=1#old", helper_routes.TokenHandoffFragment)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, handoffRequest("signed.jwt"))
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", w.Code)
+	}
+	loc, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Host != "spa.example" || loc.Path != "/app" || loc.RawQuery != "x=1" {
+		t.Fatalf("unexpected target %q", loc)
+	}
+	fragment, _ := url.ParseQuery(loc.Fragment)
+	if fragment.Get(helper_routes.TokenHandoffKey) != "signed.jwt" {
+		t.Fatalf("expected token in fragment, got %q", loc.Fragment)
+	}
+	if w.Header().Get("Cache-Control") != "no-store" || w.Header().Get("Referrer-Policy") != "no-referrer" {
+		t.Fatalf("expected no-store and no-referrer headers")
+	}
+}
+
+func TestTokenHandoff_Bootstrap(t *testing.T) {
+	h := helper_routes.NewTokenHandoffHTTP("/spa/", helper_routes.TokenHandoffBootstrap)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, handoffRequest("signed.jwt"))
+
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("expected HTML page, got %d", w.Code)
+	}
+	for _, want := range []string{`sessionStorage.setItem("lti_token", "signed.jwt")`, `window.location.replace("/spa/")`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected page to contain %q, got %s", want, body)
+		}
+	}
+}
+
+func TestTokenHandoff_RequiresSession(t *testing.T) {
+	h := helper_routes.NewTokenHandoffHTTP("/spa/", helper_routes.TokenHandoffFragment)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, handoffRequest(""))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+}
@@ -0,0 +1,79 @@
+package helper_routes
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+
+	helper_routes_assets "github.com/vizdos-enterprises/go-lti/internal/adapters/helper_routes/assets"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+// TokenHandoffKey is the fragment parameter and sessionStorage key the
+// session token is handed over under.
+const TokenHandoffKey = "lti_token"
+
+type TokenHandoffMode uint8
+
+const (
+	// TokenHandoffFragment redirects to the target with the token in the URL
+	// fragment, which browsers never send to a server.
+	TokenHandoffFragment TokenHandoffMode = iota
+
+	// TokenHandoffBootstrap serves a page that puts the token in
+	// sessionStorage and then opens the target. The target must share the
+	// tool's origin.
+	TokenHandoffBootstrap
+)
+
+type tokenHandoffHTTP struct {
+	target string
+	mode   TokenHandoffMode
+	tpl    *template.Template
+}
+
+// NewTokenHandoffHTTP hands the verified session token to a single page app at
+// target, so it can call APIs with an Authorization: Bearer header. It must run
+// behind a session verifier.
+func NewTokenHandoffHTTP(target string, mode TokenHandoffMode) *tokenHandoffHTTP {
+	return &tokenHandoffHTTP{
+		target: target,
+		mode:   mode,
+		tpl:    template.Must(template.New("token_bootstrap").Parse(string(helper_routes_assets.TokenBootstrapHTML))),
+	}
+}
+
+func (h *tokenHandoffHTTP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := lti_domain.RawSessionFromContext(r.Context())
+	if !ok || token == "" {
+		http.Error(w, "Invalid LTI session", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	if h.mode == TokenHandoffBootstrap {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err := h.tpl.Execute(w, struct {
+			StorageKey string
+			Token      string
+			Target     string
+		}{TokenHandoffKey, token, h.target})
+		if err != nil {
+			http.Error(w, "Failed to render token handoff", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	target, err := url.Parse(h.target)
+	if err != nil {
+		http.Error(w, "Invalid handoff target", http.StatusInternalServerError)
+		return
+	}
+	target.Fragment = ""
+	target.RawFragment = ""
+	fragment := url.Values{TokenHandoffKey: {token}}.Encode()
+
+	http.Redirect(w, r, target.String()+"#"+fragment, http.StatusSeeOther)
+}
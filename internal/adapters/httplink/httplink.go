@@ -0,0 +1,50 @@
+package httplink
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Parse extracts the URL for each relation in RFC 8288 Link headers, e.g.
+// `<https://lms.example/members?p=2>; rel="next"`.
+func Parse(h http.Header) map[string]string {
+	links := map[string]string{}
+
+	for _, header := range h.Values("Link") {
+		for _, part := range strings.Split(header, ",") {
+			segments := strings.Split(part, ";")
+			target := strings.TrimSpace(segments[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			target = strings.Trim(target, "<>")
+
+			for _, param := range segments[1:] {
+				key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+					links[strings.ToLower(rel)] = target
+				}
+			}
+		}
+	}
+
+	return links
+}
+
+// SameOrigin reports whether a and b share a scheme and host. Service clients
+// use it before sending a platform token to a URL they were handed.
+func SameOrigin(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Host != "" && ua.Scheme == ub.Scheme && ua.Host == ub.Host
+}
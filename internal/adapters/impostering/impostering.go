@@ -1,14 +1,15 @@
 package impostering
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/session"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
 )
@@ -21,6 +22,10 @@ type ImposteringService struct {
 	audience         []string
 	sessionAud       []string
 
+	policy lti_domain.Policy
+
+	launchHooks lti_ports.LaunchHooks
+
 	logger lti_ports.Logger
 }
 
@@ -44,7 +49,8 @@ func (s *ImposteringService) HandleImposterLaunch(w http.ResponseWriter, r *http
 		return
 	}
 
-	if !strings.HasPrefix(jwt.ImposterLaunchRedirect, "/lti/app") {
+	app := lti_domain.RoutesFromContext(r.Context()).App()
+	if jwt.ImposterLaunchRedirect != app && !strings.HasPrefix(jwt.ImposterLaunchRedirect, app+"/") {
 		http.Error(w, "invalid imposter launch redirect", http.StatusBadRequest)
 		return
 	}
@@ -86,31 +92,35 @@ func (s *ImposteringService) HandleImposterLaunch(w http.ResponseWriter, r *http
 
 	redirect := jwt.ImposterLaunchRedirect
 	jwt.Audience = s.sessionAud
-	jwt.ID = uuid.New().String()
+	jwt.SessionID = uuid.New().String()
 	jwt.ImposterLaunchRedirect = ""
-	signed, err := s.sessionSigner.Sign(jwt, time.Hour)
+	// The session starts now, not when the imposter token was minted.
+	jwt.SessionStart = nil
+	jwt.IssuedAt = nil
+
+	if err := s.launchHooks.OnLaunch(r.Context(), nil, &jwt); err != nil {
+		msg := "launch failed"
+		var denied *lti_domain.LaunchDeniedError
+		if errors.As(err, &denied) {
+			msg = denied.Message
+			s.logger.Warn("imposter launch denied by hook", "reason", denied.Message, "for_user", jwt.UserInfo.UserID)
+		} else {
+			s.logger.Error("imposter launch hook failed", "error", err, "for_user", jwt.UserInfo.UserID)
+		}
+		http.Redirect(w, r, lti_domain.RoutesFromContext(r.Context()).ErrorURL(msg), http.StatusSeeOther)
+		return
+	}
+
+	signed, _, err := session.Sign(s.sessionSigner, jwt, s.policy, time.Now())
 	if err != nil {
 		s.logger.Error("failed to sign internal jwt for impostering session", "error", err)
 		http.Error(w, "internal jwt creation failed", http.StatusInternalServerError)
 		return
 	}
 
-	useSecureCookie := true
-	if os.Getenv("INSECURE_COOKIES") == "true" {
-		useSecureCookie = false
-	}
-	cookie := &http.Cookie{
-		Name:     lti_domain.ContextKey_Session,
-		Value:    signed,
-		Path:     "/lti/app/",
-		HttpOnly: true,
-		Secure:   useSecureCookie,
-		SameSite: http.SameSiteNoneMode,
-	}
-
-	s.logger.Info("impostering session started", "src", jwt.ImposteringSrc, "for_user", jwt.UserInfo.UserID, "impostering_id", jwt.ID, "redirect", redirect)
+	s.logger.Info("impostering session started", "src", jwt.ImposteringSrc, "for_user", jwt.UserInfo.UserID, "impostering_id", jwt.SessionID, "redirect", redirect)
 
-	http.SetCookie(w, cookie)
+	session.SetCookies(w, r, signed, app+"/")
 	http.Redirect(w, r, redirect, http.StatusFound)
 }
 
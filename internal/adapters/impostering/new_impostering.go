@@ -1,6 +1,7 @@
 package impostering
 
 import (
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_logger"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
 )
@@ -21,6 +22,8 @@ func NewImpostering(opts ...lti_ports.ImposteringOption) lti_ports.Impostering {
 		panic("an incoming verifier is required for a launcher. Call with WithIncomingVerifier")
 	}
 
+	l.policy = l.policy.WithDefaults()
+
 	return l
 }
 
@@ -52,6 +55,24 @@ func WithSessionAudience(audience []string) lti_ports.ImposteringOption {
 	}
 }
 
+// WithSessionPolicy sets the session TTL and maximum lifetime used for
+// imposter sessions.
+func WithSessionPolicy(policy lti_domain.Policy) lti_ports.ImposteringOption {
+	return func(l lti_ports.Impostering) {
+		cast := l.(*ImposteringService)
+		cast.policy = policy
+	}
+}
+
+// WithLaunchHooks runs hooks, in order, on every imposter launch before its
+// session is issued.
+func WithLaunchHooks(hooks ...lti_ports.LaunchHook) lti_ports.ImposteringOption {
+	return func(l lti_ports.Impostering) {
+		cast := l.(*ImposteringService)
+		cast.launchHooks = append(cast.launchHooks, hooks...)
+	}
+}
+
 func WithLogger(logger lti_ports.Logger) lti_ports.ImposteringOption {
 	return func(l lti_ports.Impostering) {
 		cast := l.(*ImposteringService)
@@ -0,0 +1,62 @@
+package launcher1dot3
+
+import (
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+const ltiVersion = "1.3.0"
+
+// idTokenError is a failed id_token check. Code is stable so individual
+// failures can be searched for in logs.
+type idTokenError struct {
+	Code string
+	Err  error
+}
+
+func (e *idTokenError) Error() string { return e.Err.Error() }
+func (e *idTokenError) Unwrap() error { return e.Err }
+
+const (
+	ErrCodeIssuer          = "IDT-ISS"
+	ErrCodeAudience        = "IDT-AUD"
+	ErrCodeAuthorizedParty = "IDT-AZP"
+	ErrCodeDeploymentID    = "IDT-DEP"
+	ErrCodeVersion         = "IDT-VER"
+)
+
+// validateIDToken applies the LTI 1.3 id_token claim checks that the JWT
+// library does not: iss against the deployment's issuer, aud and azp against
+// the client ID, deployment_id against the login and the LTI version.
+func validateIDToken(claims jwt.MapClaims, issuer, clientID, deploymentID string) *idTokenError {
+	if iss, err := claims.GetIssuer(); err != nil || iss == "" || iss != issuer {
+		return &idTokenError{Code: ErrCodeIssuer, Err: lti_domain.ErrInvalidIssuer}
+	}
+
+	aud, err := claims.GetAudience()
+	if err != nil || !slices.Contains(aud, clientID) {
+		return &idTokenError{Code: ErrCodeAudience, Err: lti_domain.ErrInvalidAudience}
+	}
+
+	azp, hasAZP := claims["azp"]
+	if len(aud) > 1 && !hasAZP {
+		return &idTokenError{Code: ErrCodeAuthorizedParty, Err: lti_domain.ErrInvalidAuthorizedParty}
+	}
+	if hasAZP {
+		if s, ok := azp.(string); !ok || s != clientID {
+			return &idTokenError{Code: ErrCodeAuthorizedParty, Err: lti_domain.ErrInvalidAuthorizedParty}
+		}
+	}
+
+	if got, _ := claims["https://purl.imsglobal.org/spec/lti/claim/deployment_id"].(string); got == "" || got != deploymentID {
+		return &idTokenError{Code: ErrCodeDeploymentID, Err: lti_domain.ErrDeploymentIDMismatch}
+	}
+
+	if got, _ := claims["https://purl.imsglobal.org/spec/lti/claim/version"].(string); got != ltiVersion {
+		return &idTokenError{Code: ErrCodeVersion, Err: lti_domain.ErrUnsupportedLTIVersion}
+	}
+
+	return nil
+}
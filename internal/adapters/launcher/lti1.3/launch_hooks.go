@@ -0,0 +1,30 @@
+package launcher1dot3
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+// runLaunchHooks runs the configured hooks on session. When a hook fails it
+// sends the user to the auth error page and returns false.
+func (l LTI13_Launcher) runLaunchHooks(w http.ResponseWriter, r *http.Request, claims jwt.MapClaims, session *lti_domain.LTIJWT) bool {
+	err := l.launchHooks.OnLaunch(r.Context(), claims, session)
+	if err == nil {
+		return true
+	}
+
+	msg := "launch failed"
+	var denied *lti_domain.LaunchDeniedError
+	if errors.As(err, &denied) {
+		msg = denied.Message
+		l.logger.Warn("Launch denied by hook", "reason", denied.Message, "userID", session.UserInfo.UserID, "tenantID", session.TenantID)
+	} else {
+		l.logger.Error("Launch hook failed", "error", err, "userID", session.UserInfo.UserID, "tenantID", session.TenantID)
+	}
+
+	http.Redirect(w, r, lti_domain.RoutesFromContext(r.Context()).ErrorURL(msg), http.StatusSeeOther)
+	return false
+}
@@ -0,0 +1,24 @@
+package launcher1dot3
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+// launchDestination is the app path for launch: the To of the first matching
+// launch route, or the app root.
+func (l LTI13_Launcher) launchDestination(r *http.Request, launch *lti_domain.Launch) string {
+	app := lti_domain.RoutesFromContext(r.Context()).App()
+	if launch == nil {
+		return app + "/"
+	}
+
+	for _, route := range l.launchRoutes {
+		if route.Matches(launch) {
+			return app + "/" + strings.TrimLeft(route.To, "/")
+		}
+	}
+	return app + "/"
+}
@@ -6,19 +6,23 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
-	"os"
 	"slices"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/session"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
 )
 
-var _ lti_ports.Launcher = (*LTI13_Launcher)(nil)
+var (
+	_ lti_ports.Launcher           = (*LTI13_Launcher)(nil)
+	_ lti_ports.LauncherDescriptor = (*LTI13_Launcher)(nil)
+)
 
 type LTI13_Launcher struct {
 	registry   lti_ports.Registry
@@ -35,10 +39,20 @@ type LTI13_Launcher struct {
 
 	baseURL  string
 	audience []string
+	policy   lti_domain.Policy
 
+	// messageHandlers finishes launches per message type; enabledServices
+	// keeps the order they were registered in.
+	messageHandlers map[lti_domain.LTIService]lti_ports.MessageHandler
 	enabledServices []lti_domain.LTIService
 
-	deepLinkingService lti_ports.DeepLinking
+	launchHooks lti_ports.LaunchHooks
+
+	launchRoutes []lti_domain.LaunchRoute
+
+	stateBinding    bool
+	platformStorage bool
+	storageAccess   bool
 }
 
 func (l LTI13_Launcher) GetLTIVersion() string {
@@ -65,6 +79,14 @@ func (l LTI13_Launcher) GetAudience() []string {
 	return l.audience
 }
 
+func (l LTI13_Launcher) GetBaseURL() string {
+	return l.baseURL
+}
+
+func (l LTI13_Launcher) GetEnabledServices() []lti_domain.LTIService {
+	return slices.Clone(l.enabledServices)
+}
+
 func (l LTI13_Launcher) HandleOIDC(w http.ResponseWriter, r *http.Request) {
 	// Parse form-encoded body
 	if err := r.ParseForm(); err != nil {
@@ -81,6 +103,12 @@ func (l LTI13_Launcher) HandleOIDC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if lti_domain.IsDeploymentDisabled(deployment) {
+		l.logger.Warn("Login for disabled deployment", "clientID", clientID, "deploymentID", deploymentID)
+		http.Error(w, "deployment disabled", http.StatusForbidden)
+		return
+	}
+
 	iss := r.FormValue("iss")
 
 	if deployment.GetLTIIssuer() != iss {
@@ -93,8 +121,15 @@ func (l LTI13_Launcher) HandleOIDC(w http.ResponseWriter, r *http.Request) {
 	targetLink := r.FormValue("target_link_uri")
 	messageHint := r.FormValue("lti_message_hint")
 
-	if !strings.HasPrefix(targetLink, fmt.Sprintf("%s/lti/", strings.TrimRight(l.baseURL, "/"))) {
-		l.logger.Error("Invalid redirect_uri", "targetLink", targetLink, "expected", fmt.Sprintf("%s/lti/", strings.TrimRight(l.baseURL, "/")))
+	expectedTarget := strings.TrimRight(l.baseURL, "/") + lti_domain.RoutesFromContext(r.Context()).Path() + "/"
+	if !strings.HasPrefix(targetLink, expectedTarget) {
+		l.logger.Error("Invalid redirect_uri", "targetLink", targetLink, "expected", expectedTarget)
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	if !l.policy.AllowsURI(targetLink) {
+		l.logger.Error("redirect_uri not in allowed URIs", "targetLink", targetLink)
 		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
 		return
 	}
@@ -119,9 +154,23 @@ func (l LTI13_Launcher) HandleOIDC(w http.ResponseWriter, r *http.Request) {
 		Nonce:        nonce,
 		TenantID:     deployment.GetTenantID(),
 		CreatedAt:    time.Now().UTC(),
+
+		StorageTarget: r.FormValue("lti_storage_target"),
+		TargetLinkURI: targetLink,
 	}
 
-	err = l.ephemeral.SaveState(r.Context(), state, stateData, 5*time.Minute)
+	var binding string
+	if l.stateBinding {
+		binding, err = l.randomness(32)
+		if err != nil {
+			l.logger.Error("Failed to generate state binding", "error", err)
+			http.Error(w, "failed to generate state", http.StatusInternalServerError)
+			return
+		}
+		stateData.BindingHash = hashBinding(binding)
+	}
+
+	err = l.ephemeral.SaveState(r.Context(), state, stateData, l.policy.StateTTL)
 	if err != nil {
 		l.logger.Error("Failed to save state, got %s expected %s", err, "nil")
 		http.Error(w, "failed to save state", http.StatusInternalServerError)
@@ -143,6 +192,20 @@ func (l LTI13_Launcher) HandleOIDC(w http.ResponseWriter, r *http.Request) {
 
 	redirectURL := fmt.Sprintf("%s?%s", deployment.GetLTIAuthEndpoint(), v.Encode())
 
+	if l.stateBinding {
+		l.setStateCookie(w, r, state, binding, targetLink)
+	}
+
+	if login, ok := l.storageLogin(r.Context(), state, &stateData); ok {
+		stored, err := l.storeLogin(w, r, login, binding, redirectURL)
+		if err != nil {
+			l.logger.Error("failed to render platform storage page", "error", err)
+		}
+		if stored {
+			return
+		}
+	}
+
 	http.Redirect(w, r, redirectURL, http.StatusFound)
 }
 
@@ -187,48 +250,56 @@ func (l LTI13_Launcher) HandleCodeSwap(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c, err := r.Cookie(lti_domain.ContextKey_CookieConfirmation)
-	if err != nil && errors.Is(err, http.ErrNoCookie) {
-		if l.fallbackAuthorizer != nil {
-			ex, err := l.generateExchangeCode(r.Context(), swapData)
-			if err != nil {
-				http.Error(w, "failed to generate exchange code", http.StatusInternalServerError)
+	cookies := lti_domain.CookiePolicyFromContext(r.Context())
+	method := lti_domain.LaunchMethodDirect
+
+	// Platform storage already tied this launch to the browser.
+	if swapData.StorageVerified {
+		method = lti_domain.LaunchMethodPlatformStorage
+	} else {
+		c, err := cookies.Read(r, lti_domain.ContextKey_CookieConfirmation)
+		if err != nil && errors.Is(err, http.ErrNoCookie) {
+			if l.fallbackAuthorizer != nil {
+				ex, err := l.generateExchangeCode(r.Context(), swapData)
+				if err != nil {
+					http.Error(w, "failed to generate exchange code", http.StatusInternalServerError)
+					return
+				}
+				l.fallbackAuthorizer.HandleFallback(w, r, ex)
 				return
 			}
-			l.fallbackAuthorizer.HandleFallback(w, r, ex)
+
+			http.Error(w, "no fallback authorizer configured", http.StatusInternalServerError)
 			return
 		}
 
-		http.Error(w, "no fallback authorizer configured", http.StatusInternalServerError)
-		return
-	}
+		if err != nil {
+			l.logger.Error("failed to get confirmation cookie", "err", err.Error())
+			http.Error(w, "failed to get confirmation cookie", http.StatusInternalServerError)
+			return
+		}
 
-	if err != nil {
-		l.logger.Error("failed to get confirmation cookie", "err", err.Error())
-		http.Error(w, "failed to get confirmation cookie", http.StatusInternalServerError)
-		return
-	}
+		if swapCode != c.Value {
+			http.Error(w, "swap not equal", http.StatusBadRequest)
+			return
+		}
 
-	if swapCode != c.Value {
-		http.Error(w, "swap not equal", http.StatusBadRequest)
-		return
+		if swapData.StorageAccess {
+			method = lti_domain.LaunchMethodStorageAccess
+		}
 	}
 
 	swapData.Claims.SessionID = rand.Text()
-	signed, err := l.signer.Sign(swapData.Claims, time.Hour)
+	signed, _, err := session.Sign(l.signer, swapData.Claims, l.policy, time.Now())
 	if err != nil {
 		l.logger.Error("failed to sign internal jwt", "error", err)
 		http.Error(w, "internal jwt creation failed", http.StatusInternalServerError)
 		return
 	}
 
-	useSecureCookie := true
-	if os.Getenv("INSECURE_COOKIES") == "true" {
-		useSecureCookie = false
-	}
 	l.telemetry.EmitLaunch(lti_domain.LaunchEvent{
 		At:          time.Now().UTC(),
-		Method:      lti_domain.LaunchMethodDirect,
+		Method:      method,
 		Success:     true,
 		Platform:    swapData.Claims.Platform.ProductFamilyCode,
 		UserAgent:   swapData.RequestorUA,
@@ -236,21 +307,19 @@ func (l LTI13_Launcher) HandleCodeSwap(w http.ResponseWriter, r *http.Request) {
 		Duration:    time.Since(swapData.StartAt),
 		Impostering: swapData.Claims.Impostering,
 	})
-	cookie := &http.Cookie{
-		Name:     lti_domain.ContextKey_Session,
-		Value:    signed,
-		Path:     swapData.To,
-		HttpOnly: true,
-		Secure:   useSecureCookie,
-		SameSite: http.SameSiteNoneMode,
-	}
-	http.SetCookie(w, cookie)
+	session.SetCookies(w, r, signed, lti_domain.RoutesFromContext(r.Context()).App()+"/")
 	http.Redirect(w, r, swapData.To, http.StatusFound)
 }
 
 func (l LTI13_Launcher) handleImpostering(w http.ResponseWriter, r *http.Request) {
 	l.logger.Warn("Impostering Started")
-	signed, err := l.signer.Sign(l.imposterJWT, time.Hour)
+	claims := *l.imposterJWT
+	claims.Custom = maps.Clone(claims.Custom)
+	claims.Extra = maps.Clone(claims.Extra)
+	if !l.runLaunchHooks(w, r, nil, &claims) {
+		return
+	}
+	signed, _, err := session.Sign(l.signer, claims, l.policy, time.Now())
 	if err != nil {
 		l.logger.Error("failed to sign internal jwt", "error", err)
 		http.Error(w, "internal jwt creation failed", http.StatusInternalServerError)
@@ -259,6 +328,20 @@ func (l LTI13_Launcher) handleImpostering(w http.ResponseWriter, r *http.Request
 	l.redirector.RedirectAfterLaunch(w, r, signed)
 }
 
+// nonceTTL keeps a consumed nonce until the id_token could no longer be
+// accepted, falling back to the policy's NonceTTL when there is no exp.
+func (l LTI13_Launcher) nonceTTL(claims jwt.MapClaims) time.Duration {
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return l.policy.NonceTTL
+	}
+	ttl := time.Until(exp.Time) + l.policy.MaxClockSkew
+	if ttl <= 0 {
+		return l.policy.NonceTTL
+	}
+	return ttl
+}
+
 func (l LTI13_Launcher) HandleLaunch(w http.ResponseWriter, r *http.Request) {
 	if l.imposterJWT != nil {
 		l.handleImpostering(w, r)
@@ -285,9 +368,42 @@ func (l LTI13_Launcher) HandleLaunch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The state cookie was blocked; fetch the binding from platform storage
+	// and come back before the state is used up.
+	if needsStorageLookup(r, stateID, stateData) {
+		l.renderStorageLookup(w, r, stateID, stateData)
+		return
+	}
+
+	storageVerified := false
+	if storage, ok := l.storageAuthorizer(); ok {
+		if login, ok := l.storageLogin(r.Context(), stateID, stateData); ok {
+			switch storage.VerifyLaunch(w, r, login) {
+			case lti_domain.StorageCheckPending:
+				return
+			case lti_domain.StorageCheckVerified:
+				storageVerified = true
+			case lti_domain.StorageCheckMismatch:
+				_ = l.ephemeral.DeleteState(r.Context(), stateID)
+				l.logger.Error("Platform storage mismatch", "clientID", stateData.ClientID, "deploymentID", stateData.DeploymentID)
+				http.Error(w, "platform storage mismatch", http.StatusUnauthorized)
+				return
+			}
+		}
+	}
+
 	// delete the used state (one-time use)
 	_ = l.ephemeral.DeleteState(r.Context(), stateID)
 
+	if stateData.BindingHash != "" {
+		l.clearStateCookie(w, r, stateID)
+		if !stateBound(r, stateID, stateData) {
+			l.logger.Error("State not bound to this browser", "clientID", stateData.ClientID, "deploymentID", stateData.DeploymentID)
+			http.Error(w, "state not bound to this browser", http.StatusUnauthorized)
+			return
+		}
+	}
+
 	// Load deployment info (for issuer and JWKS validation)
 	dep, err := l.registry.GetDeployment(r.Context(), stateData.ClientID, stateData.DeploymentID)
 	if err != nil {
@@ -295,6 +411,12 @@ func (l LTI13_Launcher) HandleLaunch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if lti_domain.IsDeploymentDisabled(dep) {
+		l.logger.Warn("Launch for disabled deployment", "clientID", stateData.ClientID, "deploymentID", stateData.DeploymentID)
+		http.Error(w, "deployment disabled", http.StatusForbidden)
+		return
+	}
+
 	// Verify the JWT
 	jwksURL := dep.GetLTIJWKSURL()
 	k, err := l.keyfunc(r.Context(), []string{jwksURL})
@@ -304,7 +426,7 @@ func (l LTI13_Launcher) HandleLaunch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := jwt.Parse(rawToken, k.Keyfunc)
+	token, err := jwt.Parse(rawToken, k.Keyfunc, jwt.WithLeeway(l.policy.MaxClockSkew))
 	if err != nil || !token.Valid {
 		l.logger.Error("Invalid JWT", "error", err)
 		http.Error(w, "invalid id_token", http.StatusUnauthorized)
@@ -316,17 +438,35 @@ func (l LTI13_Launcher) HandleLaunch(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "bad claims", http.StatusInternalServerError)
 		return
 	}
+	if idErr := validateIDToken(claims, dep.GetLTIIssuer(), stateData.ClientID, stateData.DeploymentID); idErr != nil {
+		l.logger.Error("Invalid id_token claims", "code", idErr.Code, "error", idErr.Err, "clientID", stateData.ClientID, "deploymentID", stateData.DeploymentID)
+		http.Error(w, idErr.Error(), http.StatusUnauthorized)
+		return
+	}
+
 	if claims["nonce"] != stateData.Nonce {
 		l.logger.Error("Invalid nonce used")
 		http.Error(w, "invalid nonce", http.StatusUnauthorized)
 		return
 	}
 
+	if err := l.ephemeral.ConsumeNonce(r.Context(), stateData.Nonce, l.nonceTTL(claims)); err != nil {
+		if errors.Is(err, lti_domain.ErrNonceReplayed) {
+			l.logger.Error("Replayed nonce", "clientID", stateData.ClientID, "deploymentID", stateData.DeploymentID)
+			http.Error(w, "nonce already used", http.StatusUnauthorized)
+			return
+		}
+		l.logger.Error("failed to record nonce", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	messageType, ok := claims["https://purl.imsglobal.org/spec/lti/claim/message_type"].(string)
 
 	requestType := lti_domain.LTIService(messageType)
 
-	if !ok || !slices.Contains(l.enabledServices, requestType) {
+	handler, found := l.messageHandlers[requestType]
+	if !ok || !found {
 		http.Error(w, fmt.Sprintf("invalid message type: %s", messageType), http.StatusUnauthorized)
 		return
 	}
@@ -360,14 +500,59 @@ func (l LTI13_Launcher) HandleLaunch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resourceLinkID := ""
+	resourceLinkTitle := ""
+	resourceLinkDesc := ""
 	if v, ok := claims["https://purl.imsglobal.org/spec/lti/claim/resource_link"]; ok {
 		if mapped, ok := v.(map[string]any); ok {
 			if id, ok := mapped["id"].(string); ok {
 				resourceLinkID = id
 			}
+			resourceLinkTitle, _ = mapped["title"].(string)
+			resourceLinkDesc, _ = mapped["description"].(string)
+		}
+	}
+
+	var courseType []string
+	if types, ok := ctxClaim["type"].([]any); ok {
+		for _, t := range types {
+			if str, ok := t.(string); ok {
+				courseType = append(courseType, str)
+			}
+		}
+	}
+
+	var presentation *lti_domain.LTIJWT_Presentation
+	if v, ok := claims["https://purl.imsglobal.org/spec/lti/claim/launch_presentation"].(map[string]any); ok {
+		presentation = &lti_domain.LTIJWT_Presentation{}
+		presentation.DocumentTarget, _ = v["document_target"].(string)
+		presentation.ReturnURL, _ = v["return_url"].(string)
+		presentation.Locale, _ = v["locale"].(string)
+		if width, ok := v["width"].(float64); ok {
+			presentation.Width = int(width)
+		}
+		if height, ok := v["height"].(float64); ok {
+			presentation.Height = int(height)
+		}
+	}
+
+	var lisClaim *lti_domain.LTIJWT_LIS
+	if v, ok := claims["https://purl.imsglobal.org/spec/lti/claim/lis"].(map[string]any); ok {
+		lisClaim = &lti_domain.LTIJWT_LIS{}
+		lisClaim.PersonSourcedID, _ = v["person_sourcedid"].(string)
+		lisClaim.CourseSectionSourcedID, _ = v["course_section_sourcedid"].(string)
+	}
+
+	var mentees []string
+	if v, ok := claims["https://purl.imsglobal.org/spec/lti/claim/role_scope_mentor"].([]any); ok {
+		for _, m := range v {
+			if str, ok := m.(string); ok {
+				mentees = append(mentees, str)
+			}
 		}
 	}
 
+	ltiVersion, _ := claims["https://purl.imsglobal.org/spec/lti/claim/version"].(string)
+
 	name, _ := claims["name"].(string)
 	given_name, _ := claims["given_name"].(string)
 	family_name, _ := claims["family_name"].(string)
@@ -405,19 +590,80 @@ func (l LTI13_Launcher) HandleLaunch(w http.ResponseWriter, r *http.Request) {
 		platform.Version = v
 	}
 
+	var agsClaim *lti_domain.LTIJWT_AGS
+	if v, ok := claims["https://purl.imsglobal.org/spec/lti-ags/claim/endpoint"].(map[string]any); ok {
+		agsClaim = &lti_domain.LTIJWT_AGS{}
+		if lineItems, ok := v["lineitems"].(string); ok {
+			agsClaim.LineItems = lineItems
+		}
+		if lineItem, ok := v["lineitem"].(string); ok {
+			agsClaim.LineItem = lineItem
+		}
+		if scopes, ok := v["scope"].([]any); ok {
+			for _, s := range scopes {
+				if str, ok := s.(string); ok {
+					agsClaim.Scopes = append(agsClaim.Scopes, str)
+				}
+			}
+		}
+	}
+
+	var nrpsClaim *lti_domain.LTIJWT_NRPS
+	if v, ok := claims["https://purl.imsglobal.org/spec/lti-nrps/claim/namesroleservice"].(map[string]any); ok {
+		if membershipsURL, ok := v["context_memberships_url"].(string); ok && membershipsURL != "" {
+			nrpsClaim = &lti_domain.LTIJWT_NRPS{ContextMembershipsURL: membershipsURL}
+			if versions, ok := v["service_versions"].([]any); ok {
+				for _, ver := range versions {
+					if str, ok := ver.(string); ok {
+						nrpsClaim.ServiceVersions = append(nrpsClaim.ServiceVersions, str)
+					}
+				}
+			}
+		}
+	}
+
+	var forUserClaim *lti_domain.LTIJWT_ForUser
+	if v, ok := claims["https://purl.imsglobal.org/spec/lti/claim/for_user"].(map[string]any); ok {
+		if id, ok := v["user_id"].(string); ok && id != "" {
+			forUserClaim = &lti_domain.LTIJWT_ForUser{UserID: id}
+			forUserClaim.PersonSourcedID, _ = v["person_sourcedid"].(string)
+			forUserClaim.Name, _ = v["name"].(string)
+			forUserClaim.GivenName, _ = v["given_name"].(string)
+			forUserClaim.FamilyName, _ = v["family_name"].(string)
+			forUserClaim.Email, _ = v["email"].(string)
+			if forRoles, ok := v["roles"].([]any); ok {
+				for _, role := range forRoles {
+					if str, ok := role.(string); ok {
+						forUserClaim.Roles = append(forUserClaim.Roles, lti_domain.ParseRoleURI(str))
+					}
+				}
+			}
+		}
+	}
+
 	// Build your internal JWT payload
 	internalClaims := lti_domain.LTIJWT{
-		LaunchType:       requestType,
-		TenantID:         lti_domain.TenantIDString(stateData.TenantID),
-		Deployment:       dep.GetDeploymentID(),
-		ClientID:         dep.GetLTIClientID(),
-		Custom:           customClaims,
-		LinkedResourceID: resourceLinkID,
-		Platform:         platform,
+		LaunchType:          requestType,
+		LTIVersion:          ltiVersion,
+		TenantID:            lti_domain.TenantIDString(stateData.TenantID),
+		Deployment:          dep.GetDeploymentID(),
+		ClientID:            dep.GetLTIClientID(),
+		Custom:              customClaims,
+		LinkedResourceID:    resourceLinkID,
+		LinkedResourceTitle: resourceLinkTitle,
+		LinkedResourceDesc:  resourceLinkDesc,
+		Platform:            platform,
+		AGS:                 agsClaim,
+		NRPS:                nrpsClaim,
+		ForUser:             forUserClaim,
+		LaunchPresentation:  presentation,
+		LIS:                 lisClaim,
+		RoleScopeMentor:     mentees,
 		CourseInfo: lti_domain.LTIJWT_CourseInfo{
 			CourseID:    courseID,
 			CourseLabel: courseLabel,
 			CourseTitle: courseTitle,
+			CourseType:  courseType,
 		},
 		UserInfo: lti_domain.LTIJWT_UserInfo{
 			UserID:     userID,
@@ -437,36 +683,21 @@ func (l LTI13_Launcher) HandleLaunch(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	if l.deepLinkingService != nil && l.deepLinkingService.IsDeepLinkLaunch(requestType) {
-		internalClaims.RegisteredClaims = jwt.RegisteredClaims{
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now().Add(-10 * time.Second)),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(1 * time.Hour)),
-		}
-		// Sign it
-		signed, err := l.signer.Sign(internalClaims, time.Hour)
-		if err != nil {
-			l.logger.Error("failed to sign internal jwt", "error", err)
-			http.Error(w, "internal jwt creation failed", http.StatusInternalServerError)
-			return
-		}
-
-		l.deepLinkingService.HandleLaunch(w, r, &internalClaims, signed, claims)
+	if !l.runLaunchHooks(w, r, claims, &internalClaims) {
 		return
 	}
 
-	swapToken := rand.Text()
-	err = l.ephemeral.SaveSwapToken(r.Context(), swapToken, lti_domain.SwapToken{
-		To:          "/lti/app/",
-		RequestorUA: r.Header.Get("User-Agent"),
-		Claims:      internalClaims,
-		StartAt:     time.Now().UTC(),
-	}, 30*time.Second)
-	if err != nil {
-		l.logger.Error("failed to save swap token", "error", err)
+	if err := l.storeLargeClaims(r.Context(), &internalClaims); err != nil {
+		l.logger.Error("failed to store session claims", "error", err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	l.redirector.RedirectAfterLaunch(w, r, swapToken)
+	launch := &lti_domain.Launch{
+		MessageType:   requestType,
+		Session:       &internalClaims,
+		Claims:        claims,
+		TargetLinkURI: stateData.TargetLinkURI,
+	}
+	handler.HandleMessage(w, r, launch, launchSessions{l: l, launch: launch, storageVerified: storageVerified})
 }
@@ -0,0 +1,84 @@
+package launcher1dot3
+
+import (
+	"crypto/rand"
+	"net/http"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/session"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.SessionIssuer = launchSessions{}
+
+// launchSessions issues sessions for one launch request.
+type launchSessions struct {
+	l               LTI13_Launcher
+	launch          *lti_domain.Launch
+	storageVerified bool
+}
+
+func (s launchSessions) StartSession(w http.ResponseWriter, r *http.Request, claims *lti_domain.LTIJWT, to string) {
+	if to == "" {
+		to = s.l.launchDestination(r, s.launch)
+	}
+
+	swapToken := rand.Text()
+	err := s.l.ephemeral.SaveSwapToken(r.Context(), swapToken, lti_domain.SwapToken{
+		To:          to,
+		RequestorUA: r.Header.Get("User-Agent"),
+		Claims:      *claims,
+		StartAt:     time.Now().UTC(),
+
+		StorageVerified: s.storageVerified,
+	}, s.l.policy.SwapTokenTTL)
+	if err != nil {
+		s.l.logger.Error("failed to save swap token", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	s.l.redirector.RedirectAfterLaunch(w, r, swapToken)
+}
+
+func (s launchSessions) SignSession(claims *lti_domain.LTIJWT) (string, error) {
+	now := time.Now()
+	expires, err := session.Stamp(claims, s.l.policy, now)
+	if err != nil {
+		return "", err
+	}
+	return s.l.signer.Sign(*claims, expires.Sub(now))
+}
+
+// resourceLinkHandler starts the app session for LtiResourceLinkRequest.
+type resourceLinkHandler struct{}
+
+func (resourceLinkHandler) HandleMessage(w http.ResponseWriter, r *http.Request, launch *lti_domain.Launch, sessions lti_ports.SessionIssuer) {
+	sessions.StartSession(w, r, launch.Session, "")
+}
+
+// deepLinkHandler hands LtiDeepLinkingRequest to the deep linking service.
+type deepLinkHandler struct {
+	service lti_ports.DeepLinking
+}
+
+func (h deepLinkHandler) HandleMessage(w http.ResponseWriter, r *http.Request, launch *lti_domain.Launch, sessions lti_ports.SessionIssuer) {
+	signed, err := sessions.SignSession(launch.Session)
+	if err != nil {
+		http.Error(w, "internal jwt creation failed", http.StatusInternalServerError)
+		return
+	}
+
+	h.service.HandleLaunch(w, r, launch.Session, signed, launch.Claims)
+}
+
+// submissionReviewHandler starts the app session for LtiSubmissionReviewRequest
+// at a dedicated path.
+type submissionReviewHandler struct {
+	path string
+}
+
+func (h submissionReviewHandler) HandleMessage(w http.ResponseWriter, r *http.Request, launch *lti_domain.Launch, sessions lti_ports.SessionIssuer) {
+	sessions.StartSession(w, r, launch.Session, lti_domain.RoutesFromContext(r.Context()).App()+h.path)
+}
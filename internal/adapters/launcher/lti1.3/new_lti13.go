@@ -1,7 +1,6 @@
 package launcher1dot3
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/vizdos-enterprises/go-lti/internal/adapters/fallback_authorizer"
@@ -18,11 +17,10 @@ type LauncherOptions func(*LTI13_Launcher)
 
 func NewLauncher(opts ...LauncherOptions) *LTI13_Launcher {
 	l := &LTI13_Launcher{
-		redirector: redirector.NewDefaultRedirector(""),
-		enabledServices: []lti_domain.LTIService{
-			lti_domain.LTIService_ResourceLink,
-		},
+		redirector:      redirector.NewDefaultRedirector(""),
+		messageHandlers: map[lti_domain.LTIService]lti_ports.MessageHandler{},
 	}
+	WithMessageHandler(lti_domain.LTIService_ResourceLink, resourceLinkHandler{})(l)
 	for _, opt := range opts {
 		opt(l)
 	}
@@ -31,6 +29,8 @@ func NewLauncher(opts ...LauncherOptions) *LTI13_Launcher {
 		panic("baseURL is required for a launcher. Call WithBaseURL")
 	}
 
+	l.policy = l.policy.WithDefaults()
+
 	if l.logger == nil {
 		l.logger = lti_logger.NewNoopLogger()
 	}
@@ -44,7 +44,7 @@ func NewLauncher(opts ...LauncherOptions) *LTI13_Launcher {
 	}
 
 	if l.redirector == nil {
-		l.redirector = redirector.NewDefaultRedirector(fmt.Sprintf("%s/lti/app", strings.TrimRight(l.baseURL, "/")))
+		l.redirector = redirector.NewDefaultRedirector(strings.TrimRight(l.baseURL, "/") + lti_domain.DefaultRoutes().App())
 	}
 
 	if l.signer == nil {
@@ -60,7 +60,15 @@ func NewLauncher(opts ...LauncherOptions) *LTI13_Launcher {
 	}
 
 	if l.fallbackAuthorizer == nil {
-		l.fallbackAuthorizer = fallback_authorizer.New(l.ephemeral, l.signer, l.logger, l.telemetry)
+		l.fallbackAuthorizer = fallback_authorizer.New(l.ephemeral, l.signer, l.logger, l.telemetry, l.policy)
+	}
+
+	if l.storageAccess {
+		l.fallbackAuthorizer = fallback_authorizer.NewStorageAccess(l.fallbackAuthorizer, l.ephemeral, l.logger, l.policy.SwapTokenTTL)
+	}
+
+	if l.platformStorage {
+		l.fallbackAuthorizer = fallback_authorizer.NewPlatformStorage(l.fallbackAuthorizer, l.logger)
 	}
 
 	return l
@@ -139,8 +147,79 @@ func WithImpostering(imposterJWT *lti_domain.LTIJWT) LauncherOptions {
 }
 
 func WithDeepLinking(deepLinkingService lti_ports.DeepLinking) LauncherOptions {
+	return WithMessageHandler(lti_domain.LTIService_DeepLink, deepLinkHandler{service: deepLinkingService})
+}
+
+// WithSubmissionReview accepts LtiSubmissionReviewRequest launches and sends
+// them to path under the app routes, e.g. "/grader/". The student being
+// reviewed is in the session's ForUser and the line item in its AGS claim.
+func WithSubmissionReview(path string) LauncherOptions {
+	return WithMessageHandler(lti_domain.LTIService_SubmissionReview, submissionReviewHandler{path: path})
+}
+
+// WithMessageHandler finishes launches of messageType with handler, replacing
+// any handler registered for it before. Launches of unregistered message types
+// are rejected.
+func WithMessageHandler(messageType lti_domain.LTIService, handler lti_ports.MessageHandler) LauncherOptions {
+	return func(s *LTI13_Launcher) {
+		if _, ok := s.messageHandlers[messageType]; !ok {
+			s.enabledServices = append(s.enabledServices, messageType)
+		}
+		s.messageHandlers[messageType] = handler
+	}
+}
+
+// WithPolicy sets the state and swap token TTLs, the clock skew allowed when
+// parsing the platform id_token and the permitted target_link_uri values.
+func WithPolicy(policy lti_domain.Policy) LauncherOptions {
+	return func(s *LTI13_Launcher) {
+		s.policy = policy
+	}
+}
+
+// WithStateBinding binds each OIDC login to the browser that started it with
+// a short-lived state cookie, checked when the launch arrives. Platforms that
+// send lti_storage_target also get a copy through postMessage storage for
+// browsers that block the cookie.
+func WithStateBinding() LauncherOptions {
+	return func(s *LTI13_Launcher) {
+		s.stateBinding = true
+	}
+}
+
+// WithPlatformStorage verifies launches through the platform's postMessage
+// storage when the platform sends lti_storage_target. Launches it verifies
+// skip the confirmation cookie; the rest use the configured fallback
+// authorizer, PKCE by default.
+func WithPlatformStorage() LauncherOptions {
+	return func(s *LTI13_Launcher) {
+		s.platformStorage = true
+	}
+}
+
+// WithStorageAccess asks the browser for cookie access with the Storage Access
+// API when the confirmation cookie is missing, then retries the swap. Browsers
+// that deny access fall back to the configured fallback authorizer, PKCE by
+// default.
+func WithStorageAccess() LauncherOptions {
+	return func(s *LTI13_Launcher) {
+		s.storageAccess = true
+	}
+}
+
+// WithLaunchHooks runs hooks, in order, on every launch before its session is
+// issued. Calling it again appends more hooks.
+func WithLaunchHooks(hooks ...lti_ports.LaunchHook) LauncherOptions {
+	return func(s *LTI13_Launcher) {
+		s.launchHooks = append(s.launchHooks, hooks...)
+	}
+}
+
+// WithLaunchRoutes picks where launches land under the app routes. The first
+// route that matches a launch wins; launches that match none go to the app
+// root. Calling it again appends more routes.
+func WithLaunchRoutes(routes ...lti_domain.LaunchRoute) LauncherOptions {
 	return func(s *LTI13_Launcher) {
-		s.enabledServices = append(s.enabledServices, lti_domain.LTIService_DeepLink)
-		s.deepLinkingService = deepLinkingService
+		s.launchRoutes = append(s.launchRoutes, routes...)
 	}
 }
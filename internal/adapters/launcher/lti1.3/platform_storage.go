@@ -0,0 +1,63 @@
+package launcher1dot3
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/platform_storage"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// storageAuthorizer returns the fallback authorizer when it speaks the LTI
+// Client Side postMessages spec.
+func (l LTI13_Launcher) storageAuthorizer() (lti_ports.PlatformStorageAuthorizer, bool) {
+	storage, ok := l.fallbackAuthorizer.(lti_ports.PlatformStorageAuthorizer)
+	return storage, ok
+}
+
+// storageLogin describes the values a login keeps in the platform's frame.
+// It returns false when the launch can't use platform storage.
+func (l LTI13_Launcher) storageLogin(ctx context.Context, state string, data *lti_domain.State) (lti_domain.StorageLogin, bool) {
+	if data.StorageTarget == "" {
+		return lti_domain.StorageLogin{}, false
+	}
+
+	dep, err := l.registry.GetDeployment(ctx, data.ClientID, data.DeploymentID)
+	if err != nil {
+		return lti_domain.StorageLogin{}, false
+	}
+
+	origin := platform_storage.Origin(dep.GetLTIAuthEndpoint())
+	if origin == "" {
+		return lti_domain.StorageLogin{}, false
+	}
+
+	return lti_domain.StorageLogin{
+		Target: data.StorageTarget,
+		Origin: origin,
+		State:  state,
+		Nonce:  data.Nonce,
+	}, true
+}
+
+// storeLogin writes the login to the platform's frame in a single page before
+// redirecting to redirect. The state binding is stored alongside the storage
+// authorizer's values, or on its own when no authorizer is configured, so
+// browsers that drop the state cookie can still be checked. It returns false
+// when there is nothing to store.
+func (l LTI13_Launcher) storeLogin(w http.ResponseWriter, r *http.Request, login lti_domain.StorageLogin, binding, redirect string) (bool, error) {
+	if binding != "" {
+		login.Extra = map[string]string{stateStorageKey(login.State): binding}
+	}
+
+	if storage, ok := l.storageAuthorizer(); ok {
+		return true, storage.StoreLogin(w, r, login, redirect)
+	}
+	if binding == "" {
+		return false, nil
+	}
+
+	items := []platform_storage.Item{platform_storage.NewItem(stateStorageKey(login.State), binding)}
+	return true, platform_storage.PutAndRedirect(w, login.Target, login.Origin, items, redirect)
+}
@@ -0,0 +1,70 @@
+package launcher1dot3
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/session"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.SessionRefresher = (*LTI13_Launcher)(nil)
+
+type refreshResponse struct {
+	ExpiresAt int64  `json:"expires_at"`
+	Token     string `json:"token,omitempty"`
+}
+
+func writeRefreshError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"err": msg})
+}
+
+// HandleRefresh re-signs the current session with a new expiry, keeping its
+// SessionID and start time. Cookie sessions get fresh cookies; bearer
+// sessions get the new token in the response.
+func (l LTI13_Launcher) HandleRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeRefreshError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	claims, ok := lti_domain.LTIFromContext(r.Context())
+	if !ok {
+		writeRefreshError(w, http.StatusUnauthorized, "missing token")
+		return
+	}
+
+	refreshed := *claims
+	if refreshed.ClaimsRef != "" {
+		// The verifier loaded these from the store; keep them out of the token.
+		refreshed.SplitSessionClaims()
+	}
+
+	signed, expires, err := session.Sign(l.signer, refreshed, l.policy, time.Now())
+	if errors.Is(err, lti_domain.ErrSessionLifetimeExceeded) {
+		writeRefreshError(w, http.StatusUnauthorized, "session expired")
+		return
+	}
+	if err != nil {
+		l.logger.Error("failed to sign refreshed session", "error", err)
+		writeRefreshError(w, http.StatusInternalServerError, "internal jwt creation failed")
+		return
+	}
+
+	resp := refreshResponse{ExpiresAt: expires.Unix()}
+	if r.Header.Get("Authorization") != "" {
+		resp.Token = signed
+	} else {
+		session.SetCookies(w, r, signed, lti_domain.RoutesFromContext(r.Context()).App()+"/")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(resp)
+}
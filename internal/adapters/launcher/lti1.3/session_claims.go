@@ -0,0 +1,42 @@
+package launcher1dot3
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+// sessionClaimsGrace covers the time between the launch and the start of the
+// session, which can include the fallback authorizer's exchange.
+const sessionClaimsGrace = 15 * time.Minute
+
+// storeLargeClaims moves the SessionClaims of session into the ephemeral store
+// when the encoded session is larger than Policy.MaxSessionClaimsSize.
+func (l LTI13_Launcher) storeLargeClaims(ctx context.Context, session *lti_domain.LTIJWT) error {
+	if l.policy.MaxSessionClaimsSize <= 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("encode session: %w", err)
+	}
+	if len(raw) <= l.policy.MaxSessionClaimsSize {
+		return nil
+	}
+
+	key := rand.Text()
+	claims := session.SplitSessionClaims()
+	if err := l.ephemeral.SaveSessionClaims(ctx, key, claims, l.policy.MaxSessionLifetime+sessionClaimsGrace); err != nil {
+		session.MergeSessionClaims(claims)
+		return fmt.Errorf("save session claims: %w", err)
+	}
+	session.ClaimsRef = key
+
+	l.logger.Debug("Session claims stored server-side", "size", len(raw), "userID", session.UserInfo.UserID, "tenantID", session.TenantID)
+	return nil
+}
@@ -0,0 +1,114 @@
+package launcher1dot3
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/platform_storage"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+const (
+	// stateBindingField carries the binding read back from platform storage.
+	stateBindingField = "lti_state_binding"
+	// storageCheckedField marks a launch that already asked platform storage,
+	// so a missing binding fails instead of looping.
+	storageCheckedField = "lti_storage_checked"
+)
+
+func hashBinding(binding string) string {
+	sum := sha256.Sum256([]byte(binding))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// stateCookieBase names the cookie per state, so concurrent launches in
+// several iframes don't overwrite each other.
+func stateCookieBase(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return lti_domain.ContextKey_StateBinding + "_" + hex.EncodeToString(sum[:8])
+}
+
+func stateStorageKey(state string) string {
+	return "state_binding_" + state
+}
+
+// setStateCookie binds state to this browser. The cookie is scoped to the
+// path the platform will POST the launch to.
+func (l LTI13_Launcher) setStateCookie(w http.ResponseWriter, r *http.Request, state, binding, targetLink string) {
+	path := "/"
+	if u, err := url.Parse(targetLink); err == nil && u.Path != "" {
+		path = u.Path
+	}
+
+	cookie := lti_domain.CookiePolicyFromContext(r.Context()).Cookie(stateCookieBase(state), binding, path)
+	cookie.MaxAge = int(l.policy.StateTTL.Seconds())
+	http.SetCookie(w, cookie)
+}
+
+func (l LTI13_Launcher) clearStateCookie(w http.ResponseWriter, r *http.Request, state string) {
+	cookie := lti_domain.CookiePolicyFromContext(r.Context()).Cookie(stateCookieBase(state), "", r.URL.Path)
+	cookie.MaxAge = -1
+	http.SetCookie(w, cookie)
+}
+
+// presentedBinding returns the binding from the state cookie. The form value
+// is only trusted once a login that used platform storage has come back from
+// the storage lookup; anywhere else it would let another site post its own
+// binding along with its own state.
+func presentedBinding(r *http.Request, state string, data *lti_domain.State) string {
+	if c, err := lti_domain.CookiePolicyFromContext(r.Context()).Read(r, stateCookieBase(state)); err == nil && c.Value != "" {
+		return c.Value
+	}
+	if data.StorageTarget == "" || r.FormValue(storageCheckedField) == "" {
+		return ""
+	}
+	return r.FormValue(stateBindingField)
+}
+
+// needsStorageLookup reports whether the binding should be fetched from
+// platform storage before the launch can be checked.
+func needsStorageLookup(r *http.Request, state string, data *lti_domain.State) bool {
+	return data.BindingHash != "" &&
+		data.StorageTarget != "" &&
+		r.FormValue(storageCheckedField) == "" &&
+		presentedBinding(r, state, data) == ""
+}
+
+// renderStorageLookup asks the platform's storage frame for the binding and
+// re-posts the launch with it.
+func (l LTI13_Launcher) renderStorageLookup(w http.ResponseWriter, r *http.Request, state string, data *lti_domain.State) {
+	dep, err := l.registry.GetDeployment(r.Context(), data.ClientID, data.DeploymentID)
+	if err != nil {
+		http.Error(w, "deployment not found", http.StatusUnauthorized)
+		return
+	}
+
+	// Carry every posted field over so the launch comes back unchanged.
+	fields := map[string]string{storageCheckedField: "1"}
+	for name := range r.PostForm {
+		fields[name] = r.PostFormValue(name)
+	}
+	origin := platform_storage.Origin(dep.GetLTIAuthEndpoint())
+	lookups := []platform_storage.Lookup{platform_storage.NewLookup(stateStorageKey(state), stateBindingField)}
+	err = platform_storage.GetAndPost(w, data.StorageTarget, origin, r.URL.Path, fields, lookups)
+	if err != nil {
+		l.logger.Error("failed to render platform storage lookup", "error", err)
+	}
+}
+
+// stateBound reports whether the request came from the browser that started
+// the login.
+func stateBound(r *http.Request, state string, data *lti_domain.State) bool {
+	if data.BindingHash == "" {
+		return true
+	}
+	binding := presentedBinding(r, state, data)
+	if binding == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashBinding(binding)), []byte(data.BindingHash)) == 1
+}
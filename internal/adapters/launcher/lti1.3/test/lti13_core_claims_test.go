@@ -0,0 +1,121 @@
+package launcher1dot3_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	launcher1dot3 "github.com/vizdos-enterprises/go-lti/internal/adapters/launcher/lti1.3"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+var coreClaims = jwt.MapClaims{
+	"https://purl.imsglobal.org/spec/lti/claim/resource_link": map[string]any{
+		"id":          "link-1",
+		"title":       "Week 1 Quiz",
+		"description": "Covers chapters 1 and 2",
+	},
+	"https://purl.imsglobal.org/spec/lti/claim/context": map[string]any{
+		"id":    "course-1",
+		"label": "BIO101",
+		"title": "Biology",
+		"type":  []any{"http://purl.imsglobal.org/vocab/lis/v2/course#CourseSection"},
+	},
+	"https://purl.imsglobal.org/spec/lti/claim/launch_presentation": map[string]any{
+		"document_target": "iframe",
+		"return_url":      "https://lms.example/courses/1",
+		"locale":          "en-US",
+		"width":           float64(800),
+		"height":          float64(600),
+	},
+	"https://purl.imsglobal.org/spec/lti/claim/lis": map[string]any{
+		"person_sourcedid":         "sis-user-1",
+		"course_section_sourcedid": "sis-section-1",
+	},
+	"https://purl.imsglobal.org/spec/lti/claim/role_scope_mentor": []any{"student-1", "student-2"},
+}
+
+func TestHandleLaunch_CoreClaims(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher()
+
+	messageLaunch(t, l, reg, lti_domain.LTIService_ResourceLink, coreClaims)
+	claims := savedSwap(t, reg).Claims
+
+	if claims.LTIVersion != "1.3.0" {
+		t.Errorf("expected LTI version 1.3.0, got %q", claims.LTIVersion)
+	}
+	if claims.LinkedResourceID != "link-1" || claims.LinkedResourceTitle != "Week 1 Quiz" || claims.LinkedResourceDesc != "Covers chapters 1 and 2" {
+		t.Errorf("unexpected resource link %q %q %q", claims.LinkedResourceID, claims.LinkedResourceTitle, claims.LinkedResourceDesc)
+	}
+	if want := []string{"http://purl.imsglobal.org/vocab/lis/v2/course#CourseSection"}; !reflect.DeepEqual(claims.CourseInfo.CourseType, want) {
+		t.Errorf("expected context type %v, got %v", want, claims.CourseInfo.CourseType)
+	}
+	wantPresentation := &lti_domain.LTIJWT_Presentation{
+		DocumentTarget: "iframe",
+		ReturnURL:      "https://lms.example/courses/1",
+		Locale:         "en-US",
+		Width:          800,
+		Height:         600,
+	}
+	if !reflect.DeepEqual(claims.LaunchPresentation, wantPresentation) {
+		t.Errorf("expected launch presentation %+v, got %+v", wantPresentation, claims.LaunchPresentation)
+	}
+	wantLIS := &lti_domain.LTIJWT_LIS{PersonSourcedID: "sis-user-1", CourseSectionSourcedID: "sis-section-1"}
+	if !reflect.DeepEqual(claims.LIS, wantLIS) {
+		t.Errorf("expected lis %+v, got %+v", wantLIS, claims.LIS)
+	}
+	if want := []string{"student-1", "student-2"}; !reflect.DeepEqual(claims.RoleScopeMentor, want) {
+		t.Errorf("expected role scope mentor %v, got %v", want, claims.RoleScopeMentor)
+	}
+}
+
+func TestHandleLaunch_OptionalCoreClaimsOmitted(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher()
+
+	messageLaunch(t, l, reg, lti_domain.LTIService_ResourceLink)
+	claims := savedSwap(t, reg).Claims
+
+	if claims.LaunchPresentation != nil || claims.LIS != nil || claims.RoleScopeMentor != nil || claims.CourseInfo.CourseType != nil {
+		t.Errorf("expected absent claims to stay empty, got %+v", claims)
+	}
+}
+
+func TestHandleLaunch_StoresLargeClaimsServerSide(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher(launcher1dot3.WithPolicy(lti_domain.Policy{MaxSessionClaimsSize: 1024}))
+
+	large := jwt.MapClaims{"https://purl.imsglobal.org/spec/lti/claim/custom": map[string]any{
+		"notes": strings.Repeat("x", 2048),
+	}}
+	messageLaunch(t, l, reg, lti_domain.LTIService_ResourceLink, coreClaims, large)
+	claims := savedSwap(t, reg).Claims
+
+	if claims.ClaimsRef == "" {
+		t.Fatal("expected the session to reference claims kept server-side")
+	}
+	if claims.Custom != nil || claims.LaunchPresentation != nil || claims.RoleScopeMentor != nil {
+		t.Errorf("expected large claims to be left out of the token, got %+v", claims)
+	}
+	if claims.LIS == nil || claims.LinkedResourceTitle != "Week 1 Quiz" {
+		t.Errorf("expected the other claims to stay in the token, got %+v", claims)
+	}
+
+	stored, err := reg.GetSessionClaims(t.Context(), claims.ClaimsRef)
+	if err != nil {
+		t.Fatalf("expected stored claims, got %v", err)
+	}
+	if stored.Custom["notes"] != strings.Repeat("x", 2048) || stored.LaunchPresentation == nil || len(stored.RoleScopeMentor) != 2 {
+		t.Errorf("unexpected stored claims %+v", stored)
+	}
+}
+
+func TestHandleLaunch_SmallSessionKeepsClaimsInToken(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher(launcher1dot3.WithPolicy(lti_domain.Policy{MaxSessionClaimsSize: 4096}))
+
+	messageLaunch(t, l, reg, lti_domain.LTIService_ResourceLink, coreClaims)
+	claims := savedSwap(t, reg).Claims
+
+	if claims.ClaimsRef != "" || claims.LaunchPresentation == nil {
+		t.Errorf("expected a small session to keep its claims in the token, got %+v", claims)
+	}
+}
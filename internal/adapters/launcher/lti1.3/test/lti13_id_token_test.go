@@ -0,0 +1,228 @@
+package launcher1dot3_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	launcher1dot3 "github.com/vizdos-enterprises/go-lti/internal/adapters/launcher/lti1.3"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+// validIDTokenClaims is a launch that passes every check; each case below
+// breaks exactly one thing.
+func validIDTokenClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   "https://lms.example",
+		"sub":   "user123",
+		"aud":   "client1",
+		"nonce": "nonce-123",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		"https://purl.imsglobal.org/spec/lti/claim/message_type":  "LtiResourceLinkRequest",
+		"https://purl.imsglobal.org/spec/lti/claim/deployment_id": "dep1",
+		"https://purl.imsglobal.org/spec/lti/claim/version":       "1.3.0",
+	}
+}
+
+func TestHandleLaunch_IDTokenValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutate     func(jwt.MapClaims)
+		secret     string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "valid", mutate: func(jwt.MapClaims) {}, wantStatus: http.StatusOK},
+		{
+			name:       "aud array with matching azp",
+			mutate:     func(c jwt.MapClaims) { c["aud"] = []string{"client1", "other"}; c["azp"] = "client1" },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing iss",
+			mutate:     func(c jwt.MapClaims) { delete(c, "iss") },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeIssuer,
+		},
+		{
+			name:       "iss from another platform",
+			mutate:     func(c jwt.MapClaims) { c["iss"] = "https://other.example" },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeIssuer,
+		},
+		{
+			name:       "missing aud",
+			mutate:     func(c jwt.MapClaims) { delete(c, "aud") },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeAudience,
+		},
+		{
+			name:       "aud for another client",
+			mutate:     func(c jwt.MapClaims) { c["aud"] = "client2" },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeAudience,
+		},
+		{
+			name:       "aud array without client",
+			mutate:     func(c jwt.MapClaims) { c["aud"] = []string{"client2", "client3"}; c["azp"] = "client1" },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeAudience,
+		},
+		{
+			name:       "aud array without azp",
+			mutate:     func(c jwt.MapClaims) { c["aud"] = []string{"client1", "other"} },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeAuthorizedParty,
+		},
+		{
+			name:       "azp for another client",
+			mutate:     func(c jwt.MapClaims) { c["aud"] = []string{"client1", "other"}; c["azp"] = "other" },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeAuthorizedParty,
+		},
+		{
+			name:       "azp mismatch with single aud",
+			mutate:     func(c jwt.MapClaims) { c["azp"] = "other" },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeAuthorizedParty,
+		},
+		{
+			name:       "missing deployment_id",
+			mutate:     func(c jwt.MapClaims) { delete(c, "https://purl.imsglobal.org/spec/lti/claim/deployment_id") },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeDeploymentID,
+		},
+		{
+			name:       "deployment_id from another login",
+			mutate:     func(c jwt.MapClaims) { c["https://purl.imsglobal.org/spec/lti/claim/deployment_id"] = "dep2" },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeDeploymentID,
+		},
+		{
+			name:       "missing version",
+			mutate:     func(c jwt.MapClaims) { delete(c, "https://purl.imsglobal.org/spec/lti/claim/version") },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeVersion,
+		},
+		{
+			name:       "lti 1.1 version",
+			mutate:     func(c jwt.MapClaims) { c["https://purl.imsglobal.org/spec/lti/claim/version"] = "1.1" },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   launcher1dot3.ErrCodeVersion,
+		},
+		{
+			name:       "expired",
+			mutate:     func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong signing key",
+			mutate:     func(jwt.MapClaims) {},
+			secret:     "not-the-secret",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong nonce",
+			mutate:     func(c jwt.MapClaims) { c["nonce"] = "nonce-other" },
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, reg, redir, _, logger, _ := setupLauncher()
+
+			stateID := reg.AddStateQuick("", lti_domain.State{
+				Issuer:       "https://lms.example",
+				ClientID:     "client1",
+				DeploymentID: "dep1",
+				Nonce:        "nonce-123",
+				TenantID:     "tenantA",
+				CreatedAt:    time.Now(),
+			})
+
+			claims := validIDTokenClaims()
+			tt.mutate(claims)
+
+			secret := tt.secret
+			if secret == "" {
+				secret = "test-secret"
+			}
+			rawToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			form := url.Values{"id_token": {rawToken}, "state": {stateID}}
+			req := httptest.NewRequest(http.MethodPost, "/launch", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			l.HandleLaunch(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK && !redir.DidRedirect() {
+				t.Fatalf("expected RedirectAfterLaunch to be called")
+			}
+			if tt.wantStatus != http.StatusOK && redir.DidRedirect() {
+				t.Fatalf("expected launch to be rejected before redirect")
+			}
+			if tt.wantCode != "" && !loggedCode(logger.Entries(), tt.wantCode) {
+				t.Fatalf("expected log code %s, got %+v", tt.wantCode, logger.Entries())
+			}
+		})
+	}
+}
+
+func loggedCode(entries []lti_testadapters.LogEntry, code string) bool {
+	for _, e := range entries {
+		if slices.Contains(e.KVs, any(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHandleLaunch_RejectsReplayedNonce(t *testing.T) {
+	l, reg, _, _, logger, _ := setupLauncher()
+
+	rawToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validIDTokenClaims()).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	launch := func() int {
+		// Every attempt gets a fresh state carrying the same nonce.
+		stateID := reg.AddStateQuick("", lti_domain.State{
+			ClientID:     "client1",
+			DeploymentID: "dep1",
+			Nonce:        "nonce-123",
+			CreatedAt:    time.Now(),
+		})
+		form := url.Values{"id_token": {rawToken}, "state": {stateID}}
+		req := httptest.NewRequest(http.MethodPost, "/launch", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		l.HandleLaunch(w, req)
+		return w.Code
+	}
+
+	if code := launch(); code != http.StatusOK {
+		t.Fatalf("expected first launch to succeed, got %d", code)
+	}
+	if code := launch(); code != http.StatusUnauthorized {
+		t.Fatalf("expected replayed launch to be rejected, got %d", code)
+	}
+	if !logger.ContainsMessage("Replayed nonce") {
+		t.Fatalf("expected replay to be logged, got %+v", logger.Entries())
+	}
+}
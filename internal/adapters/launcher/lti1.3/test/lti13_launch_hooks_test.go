@@ -0,0 +1,141 @@
+package launcher1dot3_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	launcher1dot3 "github.com/vizdos-enterprises/go-lti/internal/adapters/launcher/lti1.3"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+func hookedLaunch(t *testing.T, hooks ...lti_ports.LaunchHook) (*httptest.ResponseRecorder, *lti_domain.SwapToken) {
+	t.Helper()
+	l, reg, _, _, _, _ := setupLauncher(launcher1dot3.WithLaunchHooks(hooks...))
+
+	stateID := reg.AddStateQuick("", lti_domain.State{
+		Issuer:       "https://lms.example",
+		ClientID:     "client1",
+		DeploymentID: "dep1",
+		Nonce:        "nonce-123",
+		TenantID:     "tenantA",
+		CreatedAt:    time.Now(),
+	})
+	w := httptest.NewRecorder()
+	l.HandleLaunch(w, skewedLaunchRequest(t, stateID, 0))
+
+	var swap *lti_domain.SwapToken
+	reg.Swaps.Range(func(_, v any) bool {
+		swap = v.(*lti_domain.SwapToken)
+		return false
+	})
+	return w, swap
+}
+
+func TestLaunchHooks_EnrichSession(t *testing.T) {
+	var order []string
+	first := lti_ports.LaunchHookFunc(func(_ context.Context, claims jwt.MapClaims, session *lti_domain.LTIJWT) error {
+		order = append(order, "first")
+		if claims["sub"] != "user123" {
+			t.Errorf("expected id_token claims, got sub %v", claims["sub"])
+		}
+		session.SetExtra("internal_user_id", "internal-42")
+		return nil
+	})
+	second := lti_ports.LaunchHookFunc(func(_ context.Context, _ jwt.MapClaims, session *lti_domain.LTIJWT) error {
+		order = append(order, "second")
+		if session.Extra["internal_user_id"] != "internal-42" {
+			t.Errorf("expected hooks to see earlier changes")
+		}
+		session.UserInfo.Name = "Provisioned"
+		return nil
+	})
+
+	_, swap := hookedLaunch(t, first, second)
+	if swap == nil {
+		t.Fatal("expected a saved swap token")
+	}
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("expected hooks in order, got %v", order)
+	}
+	if swap.Claims.Extra["internal_user_id"] != "internal-42" || swap.Claims.UserInfo.Name != "Provisioned" {
+		t.Errorf("expected enriched session in swap token, got %+v", swap.Claims)
+	}
+}
+
+func TestLaunchHooks_Veto(t *testing.T) {
+	called := false
+	deny := lti_ports.LaunchHookFunc(func(context.Context, jwt.MapClaims, *lti_domain.LTIJWT) error {
+		return lti_domain.DenyLaunch("course not licensed")
+	})
+	after := lti_ports.LaunchHookFunc(func(context.Context, jwt.MapClaims, *lti_domain.LTIJWT) error {
+		called = true
+		return nil
+	})
+
+	w, swap := hookedLaunch(t, deny, after)
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303 to the error page, got %d", w.Code)
+	}
+	loc, _ := url.Parse(w.Header().Get("Location"))
+	if loc.Path != "/lti/auth/error" || loc.Query().Get("err") != "course not licensed" {
+		t.Errorf("unexpected error redirect %q", w.Header().Get("Location"))
+	}
+	if swap != nil {
+		t.Error("expected no swap token for a vetoed launch")
+	}
+	if called {
+		t.Error("expected later hooks to be skipped")
+	}
+}
+
+func TestLaunchHooks_FailureHidesError(t *testing.T) {
+	fail := lti_ports.LaunchHookFunc(func(context.Context, jwt.MapClaims, *lti_domain.LTIJWT) error {
+		return errors.New("database unavailable")
+	})
+
+	w, swap := hookedLaunch(t, fail)
+	loc, _ := url.Parse(w.Header().Get("Location"))
+	if w.Code != http.StatusSeeOther || loc.Query().Get("err") != "launch failed" {
+		t.Fatalf("expected generic error page, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+	if swap != nil {
+		t.Error("expected no swap token for a failed launch")
+	}
+}
+
+func TestLaunchHooks_ImposterLaunch(t *testing.T) {
+	var gotClaims jwt.MapClaims = jwt.MapClaims{}
+	imposter := &lti_domain.LTIJWT{UserInfo: lti_domain.LTIJWT_UserInfo{UserID: "dev"}}
+	hook := lti_ports.LaunchHookFunc(func(_ context.Context, claims jwt.MapClaims, session *lti_domain.LTIJWT) error {
+		gotClaims = claims
+		session.SetExtra("seen", true)
+		return nil
+	})
+
+	l, _, redir, signer, _, _ := setupLauncher(
+		launcher1dot3.WithImpostering(imposter),
+		launcher1dot3.WithLaunchHooks(hook),
+	)
+	l.HandleLaunch(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/launch", nil))
+
+	if !redir.DidRedirect() {
+		t.Fatal("expected imposter launch to complete")
+	}
+	if gotClaims != nil {
+		t.Errorf("expected nil id_token claims for imposter launches, got %v", gotClaims)
+	}
+	if signed := signer.LastSigned.(lti_domain.LTIJWT); signed.Extra["seen"] != true {
+		t.Errorf("expected hook changes in the imposter session, got %+v", signed.Extra)
+	}
+	if imposter.Extra != nil {
+		t.Error("expected the configured imposter session to stay untouched")
+	}
+}
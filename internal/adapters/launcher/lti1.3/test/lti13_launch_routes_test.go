@@ -0,0 +1,113 @@
+package launcher1dot3_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	launcher1dot3 "github.com/vizdos-enterprises/go-lti/internal/adapters/launcher/lti1.3"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var launchRoutes = launcher1dot3.WithLaunchRoutes(
+	lti_domain.LaunchRoute{TargetPath: "/lti/1.3/launch/quiz/", To: "/quiz/"},
+	lti_domain.LaunchRoute{ResourceLinkID: "link-reading", To: "/reading/"},
+	lti_domain.LaunchRoute{CustomKey: "page", CustomValue: "grades", To: "/grades"},
+	lti_domain.LaunchRoute{CustomKey: "page", To: "/pages/"},
+)
+
+func resourceLink(id string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"https://purl.imsglobal.org/spec/lti/claim/resource_link": map[string]any{"id": id},
+	}
+}
+
+func customParams(params map[string]any) jwt.MapClaims {
+	return jwt.MapClaims{
+		"https://purl.imsglobal.org/spec/lti/claim/custom": params,
+	}
+}
+
+func TestLaunchRoutes_PickDestination(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		extra  []jwt.MapClaims
+		want   string
+	}{
+		{"no match lands on app root", "https://tool.example/lti/1.3/launch", nil, "/lti/app/"},
+		{"target path prefix", "https://tool.example/lti/1.3/launch/quiz/42", nil, "/lti/app/quiz/"},
+		{"target path must be under the prefix", "https://tool.example/lti/1.3/launch/quizzes", nil, "/lti/app/"},
+		{"resource link", "https://tool.example/lti/1.3/launch", []jwt.MapClaims{resourceLink("link-reading")}, "/lti/app/reading/"},
+		{"custom parameter value", "https://tool.example/lti/1.3/launch", []jwt.MapClaims{customParams(map[string]any{"page": "grades"})}, "/lti/app/grades"},
+		{"custom parameter present", "https://tool.example/lti/1.3/launch", []jwt.MapClaims{customParams(map[string]any{"page": "other"})}, "/lti/app/pages/"},
+		{"first match wins", "https://tool.example/lti/1.3/launch/quiz/", []jwt.MapClaims{resourceLink("link-reading")}, "/lti/app/quiz/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, reg, redir, _, _, _ := setupLauncher(launchRoutes)
+
+			stateLaunch(t, l, reg, lti_domain.State{TargetLinkURI: tt.target}, lti_domain.LTIService_ResourceLink, tt.extra...)
+			if !redir.DidRedirect() {
+				t.Fatal("expected launch to complete")
+			}
+			if swap := savedSwap(t, reg); swap.To != tt.want {
+				t.Errorf("expected launch to land on %q, got %q", tt.want, swap.To)
+			}
+		})
+	}
+}
+
+func TestLaunchRoutes_MatchAllSetFields(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher(launcher1dot3.WithLaunchRoutes(
+		lti_domain.LaunchRoute{TargetPath: "/lti/1.3/launch/quiz", ResourceLinkID: "link-quiz", To: "/quiz/"},
+	))
+
+	stateLaunch(t, l, reg, lti_domain.State{TargetLinkURI: "https://tool.example/lti/1.3/launch/quiz"}, lti_domain.LTIService_ResourceLink, resourceLink("link-other"))
+	if swap := savedSwap(t, reg); swap.To != "/lti/app/" {
+		t.Errorf("expected a partial match to land on the app root, got %q", swap.To)
+	}
+}
+
+func TestLaunchRoutes_HandlerSeesTargetLinkURI(t *testing.T) {
+	var got string
+	l, reg, _, _, _, _ := setupLauncher(launcher1dot3.WithMessageHandler(lti_domain.LTIService_ResourceLink, lti_ports.MessageHandlerFunc(
+		func(w http.ResponseWriter, r *http.Request, launch *lti_domain.Launch, sessions lti_ports.SessionIssuer) {
+			got = launch.TargetLinkURI
+		},
+	)))
+
+	stateLaunch(t, l, reg, lti_domain.State{TargetLinkURI: "https://tool.example/lti/1.3/launch/quiz"}, lti_domain.LTIService_ResourceLink)
+	if got != "https://tool.example/lti/1.3/launch/quiz" {
+		t.Errorf("expected the login's target_link_uri on the launch, got %q", got)
+	}
+}
+
+func TestHandleOIDC_SavesTargetLinkURI(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher()
+
+	form := url.Values{
+		"iss":               {"https://lms.example"},
+		"client_id":         {"client1"},
+		"lti_deployment_id": {"dep1"},
+		"login_hint":        {"hint"},
+		"target_link_uri":   {"https://tool.example/lti/1.3/launch/quiz"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/oidc", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	l.HandleOIDC(httptest.NewRecorder(), req)
+
+	var state lti_domain.State
+	reg.States.Range(func(_, v any) bool {
+		state = v.(lti_domain.State)
+		return false
+	})
+	if state.TargetLinkURI != "https://tool.example/lti/1.3/launch/quiz" {
+		t.Errorf("expected target_link_uri in the login state, got %q", state.TargetLinkURI)
+	}
+}
@@ -0,0 +1,157 @@
+package launcher1dot3_test
+
+import (
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	launcher1dot3 "github.com/vizdos-enterprises/go-lti/internal/adapters/launcher/lti1.3"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+const vendorMessage lti_domain.LTIService = "VendorCustomRequest"
+
+// messageLaunch runs a launch of messageType through l, adding extra to the
+// id_token claims.
+func messageLaunch(t *testing.T, l *launcher1dot3.LTI13_Launcher, reg *lti_testadapters.FakeRegistry, messageType lti_domain.LTIService, extra ...jwt.MapClaims) *httptest.ResponseRecorder {
+	t.Helper()
+	return stateLaunch(t, l, reg, lti_domain.State{}, messageType, extra...)
+}
+
+// stateLaunch is messageLaunch from a login state built on state.
+func stateLaunch(t *testing.T, l *launcher1dot3.LTI13_Launcher, reg *lti_testadapters.FakeRegistry, state lti_domain.State, messageType lti_domain.LTIService, extra ...jwt.MapClaims) *httptest.ResponseRecorder {
+	t.Helper()
+
+	state.Issuer = "https://lms.example"
+	state.ClientID = "client1"
+	state.DeploymentID = "dep1"
+	state.Nonce = "nonce-123"
+	state.TenantID = "tenantA"
+	state.CreatedAt = time.Now()
+	stateID := reg.AddStateQuick("", state)
+
+	claims := jwt.MapClaims{
+		"iss":   "https://lms.example",
+		"sub":   "user123",
+		"nonce": "nonce-123",
+		"aud":   "client1",
+		"https://purl.imsglobal.org/spec/lti/claim/deployment_id": "dep1",
+		"https://purl.imsglobal.org/spec/lti/claim/version":       "1.3.0",
+		"https://purl.imsglobal.org/spec/lti/claim/message_type":  string(messageType),
+	}
+	for _, e := range extra {
+		maps.Copy(claims, e)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	rawToken, err := token.SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := url.Values{"id_token": {rawToken}, "state": {stateID}}
+	req := httptest.NewRequest(http.MethodPost, "/launch", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	l.HandleLaunch(w, req)
+	return w
+}
+
+func TestMessageHandler_CustomMessageType(t *testing.T) {
+	var got *lti_domain.Launch
+	handler := lti_ports.MessageHandlerFunc(func(w http.ResponseWriter, r *http.Request, launch *lti_domain.Launch, sessions lti_ports.SessionIssuer) {
+		got = launch
+		sessions.StartSession(w, r, launch.Session, "/lti/app/vendor")
+	})
+
+	l, reg, redir, _, _, _ := setupLauncher(launcher1dot3.WithMessageHandler(vendorMessage, handler))
+	messageLaunch(t, l, reg, vendorMessage)
+
+	if got == nil {
+		t.Fatal("expected the vendor handler to be called")
+	}
+	if got.MessageType != vendorMessage || got.Session.LaunchType != vendorMessage || got.Claims["sub"] != "user123" {
+		t.Errorf("unexpected launch %+v", got)
+	}
+	if !redir.DidRedirect() {
+		t.Fatal("expected StartSession to redirect after launch")
+	}
+	if swap := savedSwap(t, reg); swap.To != "/lti/app/vendor" || swap.Claims.UserInfo.UserID != "user123" {
+		t.Errorf("unexpected swap token %+v", swap)
+	}
+}
+
+func TestMessageHandler_UnregisteredTypeRejected(t *testing.T) {
+	l, reg, redir, _, _, _ := setupLauncher()
+
+	w := messageLaunch(t, l, reg, vendorMessage)
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "invalid message type") {
+		t.Fatalf("expected invalid message type, got %d %q", w.Code, w.Body.String())
+	}
+	if redir.DidRedirect() {
+		t.Error("expected no redirect for an unregistered message type")
+	}
+}
+
+func TestMessageHandler_ReplacesResourceLink(t *testing.T) {
+	called := false
+	handler := lti_ports.MessageHandlerFunc(func(w http.ResponseWriter, r *http.Request, launch *lti_domain.Launch, sessions lti_ports.SessionIssuer) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	l, reg, redir, _, _, _ := setupLauncher(launcher1dot3.WithMessageHandler(lti_domain.LTIService_ResourceLink, handler))
+	w := messageLaunch(t, l, reg, lti_domain.LTIService_ResourceLink)
+
+	if !called || w.Code != http.StatusNoContent || redir.DidRedirect() {
+		t.Fatalf("expected the replacement handler to finish the launch, got %d", w.Code)
+	}
+	if services := l.GetEnabledServices(); !slices.Equal(services, []lti_domain.LTIService{lti_domain.LTIService_ResourceLink}) {
+		t.Errorf("expected resource link to be listed once, got %v", services)
+	}
+}
+
+func TestMessageHandler_SignSession(t *testing.T) {
+	var signed string
+	var session *lti_domain.LTIJWT
+	handler := lti_ports.MessageHandlerFunc(func(w http.ResponseWriter, r *http.Request, launch *lti_domain.Launch, sessions lti_ports.SessionIssuer) {
+		var err error
+		signed, err = sessions.SignSession(launch.Session)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		session = launch.Session
+	})
+
+	l, reg, _, signer, _, _ := setupLauncher(launcher1dot3.WithMessageHandler(vendorMessage, handler))
+	messageLaunch(t, l, reg, vendorMessage)
+
+	if signed != "signed.jwt" {
+		t.Fatalf("expected signed session, got %q", signed)
+	}
+	if session.ID == "" || session.SessionID == "" || session.ExpiresAt == nil {
+		t.Errorf("expected the session to be stamped in place, got %+v", session)
+	}
+	if last := signer.LastSigned.(lti_domain.LTIJWT); last.ID != session.ID {
+		t.Errorf("expected signed claims to match the stamped session")
+	}
+}
+
+func TestMessageHandler_EnabledServicesOrder(t *testing.T) {
+	l, _, _, _, _, _ := setupLauncher(
+		launcher1dot3.WithMessageHandler(vendorMessage, lti_ports.MessageHandlerFunc(nil)),
+	)
+
+	want := []lti_domain.LTIService{lti_domain.LTIService_ResourceLink, vendorMessage}
+	if got := l.GetEnabledServices(); !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,174 @@
+package launcher1dot3_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	launcher1dot3 "github.com/vizdos-enterprises/go-lti/internal/adapters/launcher/lti1.3"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+func savedSwap(t *testing.T, reg *lti_testadapters.FakeRegistry) *lti_domain.SwapToken {
+	t.Helper()
+
+	var swap *lti_domain.SwapToken
+	reg.Swaps.Range(func(_, v any) bool {
+		swap = v.(*lti_domain.SwapToken)
+		return false
+	})
+	if swap == nil {
+		t.Fatalf("expected a saved swap token")
+	}
+	return swap
+}
+
+func TestPlatformStorage_LoginStoresStateAndNonce(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher(launcher1dot3.WithPlatformStorage())
+
+	w, stateID, _ := bindingLogin(t, l, reg, "_parent")
+	state := reg.MustGetStateT(t, stateID)
+
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(body, "lti.put_data") {
+		t.Fatalf("expected platform storage page, got %d", w.Code)
+	}
+	for _, want := range []string{"state_" + stateID, "nonce_" + state.Nonce, "https://lms.example/authorize"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected page to contain %q", want)
+		}
+	}
+	if state.StorageTarget != "_parent" {
+		t.Fatalf("expected storage target to be saved, got %q", state.StorageTarget)
+	}
+}
+
+func TestPlatformStorage_LoginWithoutTargetRedirects(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher(launcher1dot3.WithPlatformStorage())
+
+	w, _, _ := bindingLogin(t, l, reg, "")
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected plain redirect, got %d", w.Code)
+	}
+}
+
+func TestPlatformStorage_LaunchVerified(t *testing.T) {
+	l, reg, redir, _, _, _ := setupLauncher(launcher1dot3.WithPlatformStorage())
+
+	_, stateID, _ := bindingLogin(t, l, reg, "_parent")
+	useLoginNonce(reg, stateID)
+
+	w := bindingLaunch(t, l, stateID, nil, nil)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "lti.get_data") {
+		t.Fatalf("expected platform storage lookup page, got %d", w.Code)
+	}
+	if redir.DidRedirect() {
+		t.Fatalf("expected no launch before platform storage answers")
+	}
+	if _, ok := reg.States.Load(stateID); !ok {
+		t.Fatalf("expected state to survive the lookup")
+	}
+
+	w = bindingLaunch(t, l, stateID, nil, url.Values{
+		"lti_storage_lookup": {"1"},
+		"lti_storage_state":  {stateID},
+		"lti_storage_nonce":  {"nonce-123"},
+	})
+	if w.Code != http.StatusOK || !redir.DidRedirect() {
+		t.Fatalf("expected launch to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if !savedSwap(t, reg).StorageVerified {
+		t.Fatalf("expected swap to be marked as verified by platform storage")
+	}
+}
+
+func TestPlatformStorage_LaunchUnansweredUsesCookieFlow(t *testing.T) {
+	l, reg, redir, _, _, _ := setupLauncher(launcher1dot3.WithPlatformStorage())
+
+	_, stateID, _ := bindingLogin(t, l, reg, "_parent")
+	useLoginNonce(reg, stateID)
+
+	w := bindingLaunch(t, l, stateID, nil, url.Values{"lti_storage_lookup": {"1"}})
+	if w.Code != http.StatusOK || !redir.DidRedirect() {
+		t.Fatalf("expected launch to continue, got %d: %s", w.Code, w.Body.String())
+	}
+	if savedSwap(t, reg).StorageVerified {
+		t.Fatalf("expected swap to still need the cookie check")
+	}
+}
+
+func TestPlatformStorage_LaunchMismatchRejected(t *testing.T) {
+	l, reg, redir, _, logger, _ := setupLauncher(launcher1dot3.WithPlatformStorage())
+
+	_, stateID, _ := bindingLogin(t, l, reg, "_parent")
+	useLoginNonce(reg, stateID)
+
+	w := bindingLaunch(t, l, stateID, nil, url.Values{
+		"lti_storage_lookup": {"1"},
+		"lti_storage_state":  {"someone-elses-state"},
+		"lti_storage_nonce":  {"nonce-123"},
+	})
+	if w.Code != http.StatusUnauthorized || redir.DidRedirect() {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+	if !logger.ContainsMessage("Platform storage mismatch") {
+		t.Fatalf("expected mismatch to be logged, got %+v", logger.Entries())
+	}
+	if _, ok := reg.States.Load(stateID); ok {
+		t.Fatalf("expected state to be consumed")
+	}
+}
+
+func TestPlatformStorage_WithStateBinding(t *testing.T) {
+	l, reg, redir, _, _, _ := setupLauncher(launcher1dot3.WithPlatformStorage(), launcher1dot3.WithStateBinding())
+
+	w, stateID, cookie := bindingLogin(t, l, reg, "_parent")
+	useLoginNonce(reg, stateID)
+
+	if n := strings.Count(w.Body.String(), "state_binding_"+stateID); n != 1 {
+		t.Fatalf("expected the binding to be stored once with the login, got %d", n)
+	}
+
+	w = bindingLaunch(t, l, stateID, nil, url.Values{
+		"lti_state_binding":   {cookie.Value},
+		"lti_storage_checked": {"1"},
+		"lti_storage_lookup":  {"1"},
+		"lti_storage_state":   {stateID},
+		"lti_storage_nonce":   {"nonce-123"},
+	})
+	if w.Code != http.StatusOK || !redir.DidRedirect() {
+		t.Fatalf("expected launch to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleSwap_StorageVerifiedSkipsCookieCheck(t *testing.T) {
+	l, reg, _, signer, _, fallback := setupLauncher(launcher1dot3.WithPlatformStorage())
+
+	tokenID := "demo-exchange-id"
+	err := reg.SaveSwapToken(context.Background(), tokenID, lti_domain.SwapToken{
+		To:              "/lti/app/",
+		Claims:          lti_domain.LTIJWT{},
+		StorageVerified: true,
+	}, time.Second)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/swap?code="+tokenID, nil)
+	w := httptest.NewRecorder()
+	l.HandleCodeSwap(w, req)
+
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/lti/app/" {
+		t.Fatalf("expected redirect to the app, got %d", w.Code)
+	}
+	if fallback.FallbackCalled {
+		t.Fatalf("expected no fallback for a storage verified launch")
+	}
+	signer.MustHaveSigned(t)
+}
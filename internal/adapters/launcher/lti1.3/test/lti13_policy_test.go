@@ -0,0 +1,171 @@
+package launcher1dot3_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	launcher1dot3 "github.com/vizdos-enterprises/go-lti/internal/adapters/launcher/lti1.3"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+func oidcRequest(targetLink string) *http.Request {
+	form := url.Values{
+		"iss":               {"https://lms.example"},
+		"client_id":         {"client1"},
+		"lti_deployment_id": {"dep1"},
+		"login_hint":        {"hint"},
+		"target_link_uri":   {targetLink},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/oidc", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+// skewedLaunchRequest builds a launch whose id_token was issued skew in the
+// platform's future, as a platform with a fast clock would.
+func skewedLaunchRequest(t *testing.T, stateID string, skew time.Duration) *http.Request {
+	t.Helper()
+
+	issued := time.Now().Add(skew)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":   "https://lms.example",
+		"sub":   "user123",
+		"nonce": "nonce-123",
+		"aud":   "client1",
+		"https://purl.imsglobal.org/spec/lti/claim/deployment_id": "dep1",
+		"https://purl.imsglobal.org/spec/lti/claim/version":       "1.3.0",
+		"iat": issued.Unix(),
+		"nbf": issued.Unix(),
+		"exp": issued.Add(5 * time.Minute).Unix(),
+		"https://purl.imsglobal.org/spec/lti/claim/message_type": "LtiResourceLinkRequest",
+	})
+	rawToken, err := token.SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := url.Values{"id_token": {rawToken}, "state": {stateID}}
+	req := httptest.NewRequest(http.MethodPost, "/launch", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestPolicy_DefaultTTLs(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher()
+
+	w := httptest.NewRecorder()
+	l.HandleOIDC(w, oidcRequest("https://tool.example/lti/launch"))
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected redirect, got %d", w.Code)
+	}
+	if got := reg.GetLastStateTTL(); got != 5*time.Minute {
+		t.Fatalf("expected default state TTL 5m, got %s", got)
+	}
+}
+
+func TestPolicy_StateTTL(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher(launcher1dot3.WithPolicy(lti_domain.Policy{StateTTL: 90 * time.Second}))
+
+	w := httptest.NewRecorder()
+	l.HandleOIDC(w, oidcRequest("https://tool.example/lti/launch"))
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected redirect, got %d", w.Code)
+	}
+	if got := reg.GetLastStateTTL(); got != 90*time.Second {
+		t.Fatalf("expected state TTL 90s, got %s", got)
+	}
+}
+
+func TestPolicy_AllowedURIs(t *testing.T) {
+	policy := lti_domain.Policy{AllowedURIs: []string{
+		"https://tool.example/lti/1.3/launch",
+		"https://tool.example/lti/courses/",
+	}}
+
+	tests := []struct {
+		target     string
+		wantStatus int
+	}{
+		{"https://tool.example/lti/1.3/launch", http.StatusFound},
+		{"https://tool.example/lti/courses/42", http.StatusFound},
+		{"https://tool.example/lti/1.3/launch/extra", http.StatusBadRequest},
+		{"https://tool.example/lti/other", http.StatusBadRequest},
+		{"https://evil.example/lti/1.3/launch", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			l, _, _, _, _, _ := setupLauncher(launcher1dot3.WithPolicy(policy))
+
+			w := httptest.NewRecorder()
+			l.HandleOIDC(w, oidcRequest(tt.target))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestPolicy_MaxClockSkew(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     lti_domain.Policy
+		skew       time.Duration
+		wantStatus int
+	}{
+		{"no leeway rejects future token", lti_domain.Policy{}, 30 * time.Second, http.StatusUnauthorized},
+		{"leeway accepts skewed token", lti_domain.Policy{MaxClockSkew: time.Minute}, 30 * time.Second, http.StatusOK},
+		{"leeway still rejects beyond skew", lti_domain.Policy{MaxClockSkew: time.Minute}, 2 * time.Minute, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, reg, redir, _, _, _ := setupLauncher(launcher1dot3.WithPolicy(tt.policy))
+
+			stateID := reg.AddStateQuick("", lti_domain.State{
+				Issuer:       "https://lms.example",
+				ClientID:     "client1",
+				DeploymentID: "dep1",
+				Nonce:        "nonce-123",
+				TenantID:     "tenantA",
+				CreatedAt:    time.Now(),
+			})
+
+			w := httptest.NewRecorder()
+			l.HandleLaunch(w, skewedLaunchRequest(t, stateID, tt.skew))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusOK && !redir.DidRedirect() {
+				t.Fatalf("expected RedirectAfterLaunch to be called")
+			}
+		})
+	}
+}
+
+func TestPolicy_SwapTokenTTL(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher(launcher1dot3.WithPolicy(lti_domain.Policy{SwapTokenTTL: 2 * time.Minute}))
+
+	stateID := reg.AddStateQuick("", lti_domain.State{
+		ClientID:     "client1",
+		DeploymentID: "dep1",
+		Nonce:        "nonce-123",
+		CreatedAt:    time.Now(),
+	})
+
+	w := httptest.NewRecorder()
+	l.HandleLaunch(w, skewedLaunchRequest(t, stateID, 0))
+
+	if got := reg.GetLastSwapTokenTTL(); got != 2*time.Minute {
+		t.Fatalf("expected swap token TTL 2m, got %s", got)
+	}
+}
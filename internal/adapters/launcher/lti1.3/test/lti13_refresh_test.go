@@ -0,0 +1,174 @@
+package launcher1dot3_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+func refreshRequest(claims *lti_domain.LTIJWT) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/lti/auth/refresh", nil)
+	return req.WithContext(lti_domain.ContextWithLTI(req.Context(), claims))
+}
+
+func sessionClaims(start time.Time) *lti_domain.LTIJWT {
+	return &lti_domain.LTIJWT{
+		SessionID:    "session-123",
+		SessionStart: jwt.NewNumericDate(start),
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(start),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+}
+
+func TestHandleRefresh_CookieSession(t *testing.T) {
+	l, _, _, signer, _, _ := setupLauncher()
+	signer.ReturnSignedValue = "refreshed"
+
+	start := time.Now().Add(-30 * time.Minute)
+	w := httptest.NewRecorder()
+	l.HandleRefresh(w, refreshRequest(sessionClaims(start)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	signed, ok := signer.LastSigned.(lti_domain.LTIJWT)
+	if !ok {
+		t.Fatalf("expected LTIJWT claims, got %T", signer.LastSigned)
+	}
+	if signed.SessionID != "session-123" {
+		t.Errorf("expected SessionID to be kept, got %q", signed.SessionID)
+	}
+	if !signed.SessionStart.Time.Equal(start.Truncate(time.Second)) {
+		t.Errorf("expected session start to be kept, got %v", signed.SessionStart.Time)
+	}
+	if time.Until(signed.ExpiresAt.Time) < 55*time.Minute {
+		t.Errorf("expected expiry to slide forward, got %v", signed.ExpiresAt.Time)
+	}
+
+	var body struct {
+		ExpiresAt int64  `json:"expires_at"`
+		Token     string `json:"token"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body.ExpiresAt != signed.ExpiresAt.Unix() {
+		t.Errorf("expected expires_at %d, got %d", signed.ExpiresAt.Unix(), body.ExpiresAt)
+	}
+	if body.Token != "" {
+		t.Error("expected no token in the body for cookie sessions")
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 2 {
+		t.Fatalf("expected session and refresh cookies, got %d", len(cookies))
+	}
+	if cookies[0].Value != "refreshed" || cookies[0].Path != "/lti/app/" {
+		t.Errorf("unexpected session cookie %+v", cookies[0])
+	}
+	if cookies[1].Path != "/lti/auth/" {
+		t.Errorf("expected refresh cookie scoped to the auth routes, got %q", cookies[1].Path)
+	}
+}
+
+func TestHandleRefresh_BearerSession(t *testing.T) {
+	l, _, _, signer, _, _ := setupLauncher()
+	signer.ReturnSignedValue = "refreshed"
+
+	req := refreshRequest(sessionClaims(time.Now()))
+	req.Header.Set("Authorization", "Bearer old")
+
+	w := httptest.NewRecorder()
+	l.HandleRefresh(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("expected no cookies for bearer sessions")
+	}
+
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body.Token != "refreshed" {
+		t.Errorf("expected refreshed token, got %q", body.Token)
+	}
+}
+
+func TestHandleRefresh_CappedByMaxLifetime(t *testing.T) {
+	l, _, _, signer, _, _ := setupLauncher()
+
+	start := time.Now().Add(-12*time.Hour + 10*time.Minute)
+	w := httptest.NewRecorder()
+	l.HandleRefresh(w, refreshRequest(sessionClaims(start)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	signed := signer.LastSigned.(lti_domain.LTIJWT)
+	if limit := start.Add(12 * time.Hour); signed.ExpiresAt.Time.After(limit) {
+		t.Errorf("expected expiry capped at %v, got %v", limit, signed.ExpiresAt.Time)
+	}
+}
+
+func TestHandleRefresh_LifetimeExceeded(t *testing.T) {
+	l, _, _, signer, _, _ := setupLauncher()
+
+	w := httptest.NewRecorder()
+	l.HandleRefresh(w, refreshRequest(sessionClaims(time.Now().Add(-13*time.Hour))))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+	signer.MustNotHaveSigned(t)
+}
+
+func TestHandleRefresh_RequiresPost(t *testing.T) {
+	l, _, _, signer, _, _ := setupLauncher()
+
+	req := refreshRequest(sessionClaims(time.Now()))
+	req.Method = http.MethodGet
+
+	w := httptest.NewRecorder()
+	l.HandleRefresh(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+	signer.MustNotHaveSigned(t)
+}
+
+func TestHandleRefresh_KeepsStoredClaimsOutOfToken(t *testing.T) {
+	l, _, _, signer, _, _ := setupLauncher()
+
+	claims := sessionClaims(time.Now().Add(-time.Minute))
+	claims.ClaimsRef = "ref-1"
+	claims.Custom = map[string]any{"k": "v"}
+	claims.RoleScopeMentor = []string{"student-1"}
+
+	w := httptest.NewRecorder()
+	l.HandleRefresh(w, refreshRequest(claims))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	signed := signer.LastSigned.(lti_domain.LTIJWT)
+	if signed.ClaimsRef != "ref-1" || signed.Custom != nil || signed.RoleScopeMentor != nil {
+		t.Errorf("expected refreshed token to reference its stored claims only, got %+v", signed)
+	}
+	if claims.Custom["k"] != "v" {
+		t.Errorf("expected the request's session to be left intact")
+	}
+}
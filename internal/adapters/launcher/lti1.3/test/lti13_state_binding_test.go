@@ -0,0 +1,241 @@
+package launcher1dot3_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	launcher1dot3 "github.com/vizdos-enterprises/go-lti/internal/adapters/launcher/lti1.3"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+// bindingLogin runs the OIDC login and returns the saved state ID and the
+// state cookie that was set for it.
+func bindingLogin(t *testing.T, l *launcher1dot3.LTI13_Launcher, reg *lti_testadapters.FakeRegistry, storageTarget string) (*httptest.ResponseRecorder, string, *http.Cookie) {
+	t.Helper()
+
+	form := url.Values{
+		"iss":               {"https://lms.example"},
+		"client_id":         {"client1"},
+		"lti_deployment_id": {"dep1"},
+		"login_hint":        {"hint"},
+		"target_link_uri":   {"https://tool.example/lti/1.3/launch"},
+	}
+	if storageTarget != "" {
+		form.Set("lti_storage_target", storageTarget)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/oidc", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	l.HandleOIDC(w, req)
+
+	var stateID string
+	reg.States.Range(func(k, _ any) bool {
+		stateID = k.(string)
+		return false
+	})
+	if stateID == "" {
+		t.Fatalf("expected a saved state, got status %d", w.Code)
+	}
+
+	var stateCookie *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if strings.HasPrefix(c.Name, lti_domain.ContextKey_StateBinding+"_") {
+			stateCookie = c
+		}
+	}
+	return w, stateID, stateCookie
+}
+
+func bindingLaunch(t *testing.T, l *launcher1dot3.LTI13_Launcher, stateID string, cookie *http.Cookie, extra url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rawToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validIDTokenClaims()).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := url.Values{"id_token": {rawToken}, "state": {stateID}}
+	for k, v := range extra {
+		form[k] = v
+	}
+	req := httptest.NewRequest(http.MethodPost, "/lti/1.3/launch", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	l.HandleLaunch(w, req)
+	return w
+}
+
+// useLoginNonce makes the id_token's nonce match the state created by login.
+func useLoginNonce(reg *lti_testadapters.FakeRegistry, stateID string) {
+	v, _ := reg.States.Load(stateID)
+	state := v.(lti_domain.State)
+	state.Nonce = "nonce-123"
+	reg.States.Store(stateID, state)
+}
+
+func TestStateBinding_LoginSetsCookie(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher(launcher1dot3.WithStateBinding())
+
+	w, stateID, cookie := bindingLogin(t, l, reg, "")
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected redirect, got %d", w.Code)
+	}
+	if cookie == nil {
+		t.Fatalf("expected a state cookie")
+	}
+	if cookie.Path != "/lti/1.3/launch" || !cookie.HttpOnly || !cookie.Secure || cookie.MaxAge <= 0 {
+		t.Fatalf("unexpected state cookie %+v", cookie)
+	}
+	if state := reg.MustGetStateT(t, stateID); state.BindingHash == "" || state.BindingHash == cookie.Value {
+		t.Fatalf("expected state to hold a hash of the cookie value, got %q", state.BindingHash)
+	}
+}
+
+func TestStateBinding_LaunchWithCookie(t *testing.T) {
+	l, reg, redir, _, _, _ := setupLauncher(launcher1dot3.WithStateBinding())
+
+	_, stateID, cookie := bindingLogin(t, l, reg, "")
+	useLoginNonce(reg, stateID)
+
+	w := bindingLaunch(t, l, stateID, cookie, nil)
+
+	if w.Code != http.StatusOK || !redir.DidRedirect() {
+		t.Fatalf("expected launch to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	cleared := false
+	for _, c := range w.Result().Cookies() {
+		if c.Name == cookie.Name && c.MaxAge < 0 {
+			cleared = true
+		}
+	}
+	if !cleared {
+		t.Fatalf("expected state cookie to be cleared")
+	}
+}
+
+func TestStateBinding_RejectsOtherBrowser(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie func(*http.Cookie) *http.Cookie
+		form   func(*http.Cookie) url.Values
+	}{
+		{"missing cookie", func(*http.Cookie) *http.Cookie { return nil }, nil},
+		{"wrong cookie", func(c *http.Cookie) *http.Cookie { return &http.Cookie{Name: c.Name, Value: "forged"} }, nil},
+		{
+			// Another site posting a binding it holds must not stand in for
+			// the cookie when the login never used platform storage.
+			"posted binding without platform storage",
+			func(*http.Cookie) *http.Cookie { return nil },
+			func(c *http.Cookie) url.Values {
+				return url.Values{"lti_state_binding": {c.Value}, "lti_storage_checked": {"1"}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, reg, redir, _, logger, _ := setupLauncher(launcher1dot3.WithStateBinding())
+
+			_, stateID, cookie := bindingLogin(t, l, reg, "")
+			useLoginNonce(reg, stateID)
+
+			var form url.Values
+			if tt.form != nil {
+				form = tt.form(cookie)
+			}
+			w := bindingLaunch(t, l, stateID, tt.cookie(cookie), form)
+
+			if w.Code != http.StatusUnauthorized || redir.DidRedirect() {
+				t.Fatalf("expected 401, got %d", w.Code)
+			}
+			if !logger.ContainsMessage("State not bound to this browser") {
+				t.Fatalf("expected binding failure to be logged, got %+v", logger.Entries())
+			}
+			if _, ok := reg.States.Load(stateID); ok {
+				t.Fatalf("expected state to be consumed")
+			}
+		})
+	}
+}
+
+func TestStateBinding_StorageTargetFallback(t *testing.T) {
+	l, reg, redir, _, _, _ := setupLauncher(launcher1dot3.WithStateBinding())
+
+	w, stateID, cookie := bindingLogin(t, l, reg, "_parent")
+	useLoginNonce(reg, stateID)
+
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(body, "lti.put_data") {
+		t.Fatalf("expected platform storage page, got %d", w.Code)
+	}
+	if !strings.Contains(body, "https://lms.example") {
+		t.Fatalf("expected platform origin in page")
+	}
+
+	// Safari dropped the cookie: the launch asks platform storage first.
+	w = bindingLaunch(t, l, stateID, nil, url.Values{"lti_storage_target": {"_parent"}})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "lti.get_data") {
+		t.Fatalf("expected platform storage lookup page, got %d", w.Code)
+	}
+	for _, field := range []string{"id_token", "state", "lti_storage_target", "lti_storage_checked"} {
+		if !strings.Contains(w.Body.String(), `name="`+field+`"`) {
+			t.Fatalf("expected the lookup to re-post %s", field)
+		}
+	}
+	if redir.DidRedirect() {
+		t.Fatalf("expected no launch before the binding is checked")
+	}
+	if _, ok := reg.States.Load(stateID); !ok {
+		t.Fatalf("expected state to survive the lookup")
+	}
+
+	// The page posts back with the value held by the platform.
+	w = bindingLaunch(t, l, stateID, nil, url.Values{
+		"lti_state_binding":   {cookie.Value},
+		"lti_storage_checked": {"1"},
+	})
+	if w.Code != http.StatusOK || !redir.DidRedirect() {
+		t.Fatalf("expected launch to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestStateBinding_StorageTargetMissingValue(t *testing.T) {
+	l, reg, redir, _, _, _ := setupLauncher(launcher1dot3.WithStateBinding())
+
+	_, stateID, _ := bindingLogin(t, l, reg, "_parent")
+	useLoginNonce(reg, stateID)
+
+	w := bindingLaunch(t, l, stateID, nil, url.Values{
+		"lti_state_binding":   {""},
+		"lti_storage_checked": {"1"},
+	})
+	if w.Code != http.StatusUnauthorized || redir.DidRedirect() {
+		t.Fatalf("expected 401 when platform storage has no binding, got %d", w.Code)
+	}
+}
+
+func TestStateBinding_DisabledByDefault(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher()
+
+	w, stateID, cookie := bindingLogin(t, l, reg, "_parent")
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected plain redirect, got %d", w.Code)
+	}
+	if cookie != nil {
+		t.Fatalf("expected no state cookie without WithStateBinding")
+	}
+	if state := reg.MustGetStateT(t, stateID); state.BindingHash != "" {
+		t.Fatalf("expected unbound state")
+	}
+}
@@ -0,0 +1,72 @@
+package launcher1dot3_test
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	launcher1dot3 "github.com/vizdos-enterprises/go-lti/internal/adapters/launcher/lti1.3"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+var submissionReviewClaims = jwt.MapClaims{
+	"https://purl.imsglobal.org/spec/lti/claim/roles": []any{"http://purl.imsglobal.org/vocab/lis/v2/membership#Instructor"},
+	"https://purl.imsglobal.org/spec/lti/claim/for_user": map[string]any{
+		"user_id":          "student-7",
+		"person_sourcedid": "sis-7",
+		"given_name":       "Ada",
+		"family_name":      "Lovelace",
+		"name":             "Ada Lovelace",
+		"email":            "ada@example.com",
+		"roles":            []any{"http://purl.imsglobal.org/vocab/lis/v2/membership#Learner"},
+	},
+	"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint": map[string]any{
+		"lineitem": "https://lms.example/lineitems/3",
+		"scope":    []any{"https://purl.imsglobal.org/spec/lti-ags/scope/score"},
+	},
+}
+
+func TestSubmissionReview_Launch(t *testing.T) {
+	l, reg, redir, _, _, _ := setupLauncher(launcher1dot3.WithSubmissionReview("/grader/"))
+
+	messageLaunch(t, l, reg, lti_domain.LTIService_SubmissionReview, submissionReviewClaims)
+	if !redir.DidRedirect() {
+		t.Fatal("expected submission review launch to complete")
+	}
+
+	swap := savedSwap(t, reg)
+	if swap.To != "/lti/app/grader/" {
+		t.Errorf("expected launch to land on the grader, got %q", swap.To)
+	}
+
+	claims := swap.Claims
+	if claims.LaunchType != lti_domain.LTIService_SubmissionReview {
+		t.Errorf("expected submission review launch type, got %q", claims.LaunchType)
+	}
+	want := &lti_domain.LTIJWT_ForUser{
+		UserID:          "student-7",
+		PersonSourcedID: "sis-7",
+		Name:            "Ada Lovelace",
+		GivenName:       "Ada",
+		FamilyName:      "Lovelace",
+		Email:           "ada@example.com",
+		Roles:           []lti_domain.Role{lti_domain.MEMBERSHIP_LEARNER},
+	}
+	if !reflect.DeepEqual(claims.ForUser, want) {
+		t.Errorf("expected for_user %+v, got %+v", want, claims.ForUser)
+	}
+	if claims.AGS == nil || claims.AGS.LineItem != "https://lms.example/lineitems/3" {
+		t.Errorf("expected the reviewed line item in the session, got %+v", claims.AGS)
+	}
+}
+
+func TestSubmissionReview_DisabledByDefault(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher()
+
+	w := messageLaunch(t, l, reg, lti_domain.LTIService_SubmissionReview, submissionReviewClaims)
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "invalid message type") {
+		t.Fatalf("expected submission review to be rejected, got %d %q", w.Code, w.Body.String())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	launcher1dot3 "github.com/vizdos-enterprises/go-lti/internal/adapters/launcher/lti1.3"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 )
 
@@ -99,14 +100,20 @@ func TestHandleSwap_ValidSwapCodeThirdPartyCookie(t *testing.T) {
 	signer.MustHaveSigned(t)
 
 	cookies := resp.Cookies()
-	if len(cookies) != 1 {
-		t.Errorf("expected 1 cookie, got %d", len(cookies))
+	if len(cookies) != 2 {
+		t.Fatalf("expected session and refresh cookies, got %d", len(cookies))
+	}
+	if refresh := cookies[1]; refresh.Name != lti_domain.ContextKey_SessionRefresh || refresh.Path != "/lti/auth/" {
+		t.Errorf("unexpected refresh cookie %+v", refresh)
 	}
 
 	cookie := cookies[0]
 	if cookie.Name != lti_domain.ContextKey_Session {
 		t.Errorf("expected cookie name %q, got %q", lti_domain.ContextKey_Session, cookie.Name)
 	}
+	if cookie.Path != "/lti/app/" {
+		t.Errorf("expected session cookie scoped to the app routes, got %q", cookie.Path)
+	}
 	if cookie.Value != "signed.jwt" {
 		t.Errorf("expected cookie value %q, got %q", "signed.jwt", cookie.Value)
 	}
@@ -147,3 +154,154 @@ func TestHandleSwap_ValidSwapCodeFallsbackWhenNoCookie(t *testing.T) {
 	signer.MustNotHaveSigned(t)
 	fallback.MustHaveBeenCalled(t)
 }
+
+func TestHandleSwap_UsesCookiePolicyFromContext(t *testing.T) {
+	l, reg, _, signer, _, _ := setupLauncher()
+
+	tokenID := "demo-exchange-id"
+	err := reg.SaveSwapToken(context.Background(), tokenID, lti_domain.SwapToken{
+		To:     "/lti/app/",
+		Claims: lti_domain.LTIJWT{},
+	}, time.Second)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	policy := lti_domain.CookiePolicy{
+		NamePrefix:  "toolA_",
+		Domain:      "tool.example",
+		SameSite:    http.SameSiteLaxMode,
+		Partitioned: true,
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/swap?code="+tokenID, nil)
+	req = req.WithContext(lti_domain.ContextWithCookiePolicy(req.Context(), policy))
+	req.AddCookie(&http.Cookie{
+		Name:  "toolA_" + lti_domain.ContextKey_CookieConfirmation,
+		Value: tokenID,
+	})
+
+	w := httptest.NewRecorder()
+	l.HandleCodeSwap(w, req)
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected %d Redirect, got %d", http.StatusFound, w.Code)
+	}
+	signer.MustHaveSigned(t)
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 2 {
+		t.Fatalf("expected session and refresh cookies, got %d", len(cookies))
+	}
+	if refresh := cookies[1]; refresh.Name != "toolA_"+lti_domain.ContextKey_SessionRefresh || !refresh.Partitioned {
+		t.Errorf("expected refresh cookie to follow the policy, got %+v", refresh)
+	}
+
+	cookie := cookies[0]
+	if cookie.Name != "toolA_"+lti_domain.ContextKey_Session {
+		t.Errorf("expected prefixed cookie name, got %q", cookie.Name)
+	}
+	if cookie.Domain != "tool.example" {
+		t.Errorf("expected domain tool.example, got %q", cookie.Domain)
+	}
+	if cookie.SameSite != http.SameSiteLaxMode {
+		t.Errorf("expected SameSite=Lax, got %v", cookie.SameSite)
+	}
+	if !cookie.Secure || !cookie.Partitioned {
+		t.Errorf("expected Secure and Partitioned, got %+v", cookie)
+	}
+}
+
+func TestHandleSwap_HostPrefixedCookie(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher()
+
+	tokenID := "demo-exchange-id"
+	err := reg.SaveSwapToken(context.Background(), tokenID, lti_domain.SwapToken{
+		To:     "/lti/app/",
+		Claims: lti_domain.LTIJWT{},
+	}, time.Second)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	policy := lti_domain.CookiePolicy{HostPrefix: true, Domain: "tool.example", Insecure: true}
+
+	req := httptest.NewRequest(http.MethodPost, "/swap?code="+tokenID, nil)
+	req = req.WithContext(lti_domain.ContextWithCookiePolicy(req.Context(), policy))
+	req.AddCookie(&http.Cookie{
+		Name:  "__Host-" + lti_domain.ContextKey_CookieConfirmation,
+		Value: tokenID,
+	})
+
+	w := httptest.NewRecorder()
+	l.HandleCodeSwap(w, req)
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected %d Redirect, got %d", http.StatusFound, w.Code)
+	}
+
+	cookie := w.Result().Cookies()[0]
+	if cookie.Name != "__Host-"+lti_domain.ContextKey_Session {
+		t.Errorf("expected __Host- cookie name, got %q", cookie.Name)
+	}
+	if cookie.Path != "/" || cookie.Domain != "" || !cookie.Secure {
+		t.Errorf("expected Path=/, no Domain and Secure for __Host- cookie, got %+v", cookie)
+	}
+}
+
+type recordingTelemetry struct {
+	events []lti_domain.LaunchEvent
+}
+
+func (r *recordingTelemetry) EmitLaunch(ev lti_domain.LaunchEvent) {
+	r.events = append(r.events, ev)
+}
+
+func (r *recordingTelemetry) Events() <-chan lti_domain.LaunchEvent {
+	return nil
+}
+
+func TestHandleSwap_StorageAccessRetryReportsMethod(t *testing.T) {
+	tel := &recordingTelemetry{}
+	l, reg, _, _, _, _ := setupLauncher(launcher1dot3.WithTelemetry(tel), launcher1dot3.WithStorageAccess())
+
+	tokenID := "retried-swap"
+	err := reg.SaveSwapToken(context.Background(), tokenID, lti_domain.SwapToken{
+		To:            "/lti/app/",
+		StorageAccess: true,
+	}, time.Second)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/swap?code="+tokenID+"&storage_access=1", nil)
+	req.AddCookie(&http.Cookie{Name: lti_domain.ContextKey_CookieConfirmation, Value: tokenID})
+	w := httptest.NewRecorder()
+	l.HandleCodeSwap(w, req)
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected redirect, got %d", w.Code)
+	}
+	if len(tel.events) != 1 || tel.events[0].Method != lti_domain.LaunchMethodStorageAccess {
+		t.Fatalf("expected a StorageAccessLaunch event, got %+v", tel.events)
+	}
+}
+
+func TestHandleSwap_StorageAccessStillBlockedUsesFallback(t *testing.T) {
+	l, reg, _, signer, _, fallback := setupLauncher(launcher1dot3.WithStorageAccess())
+
+	tokenID := "retried-swap"
+	err := reg.SaveSwapToken(context.Background(), tokenID, lti_domain.SwapToken{
+		To:            "/lti/app/",
+		StorageAccess: true,
+	}, time.Second)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/swap?code="+tokenID+"&storage_access=1", nil)
+	l.HandleCodeSwap(httptest.NewRecorder(), req)
+
+	fallback.MustHaveBeenCalled(t)
+	signer.MustNotHaveSigned(t)
+}
@@ -15,7 +15,7 @@ import (
 )
 
 // setupLauncher creates a configured launcher with all fake adapters.
-func setupLauncher() (*launcher1dot3.LTI13_Launcher, *lti_testadapters.FakeRegistry, *lti_testadapters.FakeRedirect, *lti_testadapters.FakeSigner, *lti_testadapters.FakeLogger, *lti_testadapters.FakeFallbackAuthorizer) {
+func setupLauncher(opts ...launcher1dot3.LauncherOptions) (*launcher1dot3.LTI13_Launcher, *lti_testadapters.FakeRegistry, *lti_testadapters.FakeRedirect, *lti_testadapters.FakeSigner, *lti_testadapters.FakeLogger, *lti_testadapters.FakeFallbackAuthorizer) {
 	reg := &lti_testadapters.FakeRegistry{}
 	redir := &lti_testadapters.FakeRedirect{}
 	fallback := &lti_testadapters.FakeFallbackAuthorizer{}
@@ -24,7 +24,7 @@ func setupLauncher() (*launcher1dot3.LTI13_Launcher, *lti_testadapters.FakeRegis
 
 	reg.AddDeploymentQuick("client1", "dep1", "https://lms.example", "https://jwks.example", "tenantA")
 
-	l := launcher1dot3.NewLauncher(
+	l := launcher1dot3.NewLauncher(append([]launcher1dot3.LauncherOptions{
 		launcher1dot3.WithBaseURL("https://tool.example"),
 		launcher1dot3.WithRegistry(reg),
 		launcher1dot3.WithEphemeralStorage(reg),
@@ -33,7 +33,7 @@ func setupLauncher() (*launcher1dot3.LTI13_Launcher, *lti_testadapters.FakeRegis
 		launcher1dot3.WithLogger(logger),
 		launcher1dot3.WithFallbackAuthorizer(fallback),
 		launcher1dot3.WithKeyFunc(lti_testadapters.FakeKeyfuncProvider),
-	)
+	}, opts...)...)
 
 	return l, reg, redir, signer, logger, fallback
 }
@@ -95,6 +95,73 @@ func TestHandleOIDC_InvalidIssuer(t *testing.T) {
 	}
 }
 
+func TestHandleOIDC_TargetLinkUsesConfiguredPrefix(t *testing.T) {
+	routes := lti_domain.Routes{Prefix: "/integrations/lms"}.Normalize()
+
+	tests := []struct {
+		target     string
+		wantStatus int
+	}{
+		{"https://tool.example/integrations/lms/1.3/launch", http.StatusFound},
+		{"https://tool.example/lti/launch", http.StatusBadRequest},
+		{"https://tool.example/integrations/lmsx/launch", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			l, _, _, _, _, _ := setupLauncher()
+
+			form := url.Values{
+				"iss":               {"https://lms.example"},
+				"client_id":         {"client1"},
+				"lti_deployment_id": {"dep1"},
+				"login_hint":        {"hint"},
+				"target_link_uri":   {tt.target},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/oidc", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req = req.WithContext(lti_domain.ContextWithRoutes(req.Context(), routes))
+			w := httptest.NewRecorder()
+
+			l.HandleOIDC(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandleOIDC_DisabledDeployment(t *testing.T) {
+	l, reg, _, _, _, _ := setupLauncher()
+
+	dep := reg.MustGetDeploymentT(t, "dep1").(lti_domain.BaseLTIDeployment)
+	dep.Disabled = true
+	reg.Deployments.Store("dep1", dep)
+
+	form := url.Values{
+		"iss":               {"https://lms.example"},
+		"client_id":         {"client1"},
+		"lti_deployment_id": {"dep1"},
+		"login_hint":        {"hint"},
+		"target_link_uri":   {"https://tool.example/lti/launch"},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/oidc", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	l.HandleOIDC(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for disabled deployment, got %d", w.Code)
+	}
+	if reg.CountStates() != 0 {
+		t.Errorf("expected no state to be saved, got %d", reg.CountStates())
+	}
+}
+
 func TestHandleLaunch_Success(t *testing.T) {
 	l, reg, redir, _, logger, _ := setupLauncher()
 
@@ -110,9 +177,13 @@ func TestHandleLaunch_Success(t *testing.T) {
 
 	// Build fake JWT with valid nonce & claims
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":   "https://lms.example",
 		"sub":   "user123",
 		"nonce": "nonce-123",
-		"https://purl.imsglobal.org/spec/lti/claim/message_type": "LtiResourceLinkRequest",
+		"aud":   "client1",
+		"https://purl.imsglobal.org/spec/lti/claim/deployment_id": "dep1",
+		"https://purl.imsglobal.org/spec/lti/claim/version":       "1.3.0",
+		"https://purl.imsglobal.org/spec/lti/claim/message_type":  "LtiResourceLinkRequest",
 		"https://purl.imsglobal.org/spec/lti/claim/context": map[string]any{
 			"id": "course1", "label": "C101", "title": "Intro to Testing",
 		},
@@ -169,3 +240,119 @@ func TestHandleLaunch_InvalidState(t *testing.T) {
 		t.Errorf("expected 'Invalid or expired state' log entry")
 	}
 }
+
+func TestHandleLaunch_ParsesAGSClaim(t *testing.T) {
+	l, reg, _, _, logger, _ := setupLauncher()
+
+	stateID := reg.AddStateQuick("", lti_domain.State{
+		Issuer:       "https://lms.example",
+		ClientID:     "client1",
+		DeploymentID: "dep1",
+		Nonce:        "nonce-ags",
+		TenantID:     "tenantA",
+		CreatedAt:    time.Now(),
+	})
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":   "https://lms.example",
+		"sub":   "user123",
+		"nonce": "nonce-ags",
+		"aud":   "client1",
+		"https://purl.imsglobal.org/spec/lti/claim/deployment_id": "dep1",
+		"https://purl.imsglobal.org/spec/lti/claim/version":       "1.3.0",
+		"https://purl.imsglobal.org/spec/lti/claim/message_type":  "LtiResourceLinkRequest",
+		"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint": map[string]any{
+			"lineitems": "https://lms.example/context/1/lineitems",
+			"lineitem":  "https://lms.example/context/1/lineitems/7",
+			"scope": []any{
+				lti_domain.AGSScope_LineItem,
+				lti_domain.AGSScope_Score,
+			},
+		},
+	})
+	rawToken, _ := token.SignedString([]byte("test-secret"))
+
+	form := url.Values{"id_token": {rawToken}, "state": {stateID}}
+	req := httptest.NewRequest(http.MethodPost, "/launch", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	l.HandleLaunch(w, req)
+
+	var saved *lti_domain.SwapToken
+	reg.Swaps.Range(func(_, v any) bool {
+		saved = v.(*lti_domain.SwapToken)
+		return false
+	})
+	if saved == nil {
+		t.Logf("%+v\n", logger.Entries())
+		t.Fatalf("expected a swap token to be saved")
+	}
+
+	ags := saved.Claims.AGS
+	if ags == nil {
+		t.Fatalf("expected AGS claim on session")
+	}
+	if ags.LineItem != "https://lms.example/context/1/lineitems/7" {
+		t.Errorf("unexpected lineitem %q", ags.LineItem)
+	}
+	if !ags.HasScope(lti_domain.AGSScope_Score) {
+		t.Errorf("expected score scope, got %v", ags.Scopes)
+	}
+}
+
+func TestHandleLaunch_ParsesNRPSClaim(t *testing.T) {
+	l, reg, _, _, logger, _ := setupLauncher()
+
+	stateID := reg.AddStateQuick("", lti_domain.State{
+		Issuer:       "https://lms.example",
+		ClientID:     "client1",
+		DeploymentID: "dep1",
+		Nonce:        "nonce-nrps",
+		TenantID:     "tenantA",
+		CreatedAt:    time.Now(),
+	})
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":   "https://lms.example",
+		"sub":   "user123",
+		"nonce": "nonce-nrps",
+		"aud":   "client1",
+		"https://purl.imsglobal.org/spec/lti/claim/deployment_id": "dep1",
+		"https://purl.imsglobal.org/spec/lti/claim/version":       "1.3.0",
+		"https://purl.imsglobal.org/spec/lti/claim/message_type":  "LtiResourceLinkRequest",
+		"https://purl.imsglobal.org/spec/lti-nrps/claim/namesroleservice": map[string]any{
+			"context_memberships_url": "https://lms.example/context/1/memberships",
+			"service_versions":        []any{"2.0"},
+		},
+	})
+	rawToken, _ := token.SignedString([]byte("test-secret"))
+
+	form := url.Values{"id_token": {rawToken}, "state": {stateID}}
+	req := httptest.NewRequest(http.MethodPost, "/launch", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	l.HandleLaunch(w, req)
+
+	var saved *lti_domain.SwapToken
+	reg.Swaps.Range(func(_, v any) bool {
+		saved = v.(*lti_domain.SwapToken)
+		return false
+	})
+	if saved == nil {
+		t.Logf("%+v\n", logger.Entries())
+		t.Fatalf("expected a swap token to be saved")
+	}
+
+	nrps := saved.Claims.NRPS
+	if nrps == nil {
+		t.Fatalf("expected NRPS claim on session")
+	}
+	if nrps.ContextMembershipsURL != "https://lms.example/context/1/memberships" {
+		t.Errorf("unexpected context memberships url %q", nrps.ContextMembershipsURL)
+	}
+	if len(nrps.ServiceVersions) != 1 || nrps.ServiceVersions[0] != "2.0" {
+		t.Errorf("unexpected service versions %v", nrps.ServiceVersions)
+	}
+}
@@ -0,0 +1,74 @@
+package nrps
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/servicetoken"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_logger"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+func NewNRPS(opts ...lti_ports.NRPSOption) lti_ports.NRPS {
+	s := &NRPSService{
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		logger:     lti_logger.NewNoopLogger(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.registry == nil {
+		panic("a registry is required for NRPS. Call with WithRegistry")
+	}
+
+	if s.tokens == nil {
+		if s.signer == nil {
+			panic("a signer or token provider is required for NRPS. Call with WithSigner or WithTokenProvider")
+		}
+		s.tokens = servicetoken.NewClientCredentials(
+			servicetoken.WithSigner(s.signer),
+			servicetoken.WithHTTPClient(s.httpClient),
+			servicetoken.WithLogger(s.logger),
+		)
+	}
+
+	return s
+}
+
+func WithRegistry(registry lti_ports.Registry) lti_ports.NRPSOption {
+	return func(a lti_ports.NRPS) {
+		cast := a.(*NRPSService)
+		cast.registry = registry
+	}
+}
+
+func WithSigner(signer lti_ports.AsymetricSigner) lti_ports.NRPSOption {
+	return func(a lti_ports.NRPS) {
+		cast := a.(*NRPSService)
+		cast.signer = signer
+	}
+}
+
+// WithTokenProvider shares a ServiceTokenProvider (and its token cache) with
+// other Advantage clients instead of building one from the signer.
+func WithTokenProvider(tokens lti_ports.ServiceTokenProvider) lti_ports.NRPSOption {
+	return func(a lti_ports.NRPS) {
+		cast := a.(*NRPSService)
+		cast.tokens = tokens
+	}
+}
+
+func WithHTTPClient(client *http.Client) lti_ports.NRPSOption {
+	return func(a lti_ports.NRPS) {
+		cast := a.(*NRPSService)
+		cast.httpClient = client
+	}
+}
+
+func WithLogger(logger lti_ports.Logger) lti_ports.NRPSOption {
+	return func(a lti_ports.NRPS) {
+		cast := a.(*NRPSService)
+		cast.logger = logger
+	}
+}
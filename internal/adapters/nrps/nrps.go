@@ -0,0 +1,169 @@
+package nrps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/httplink"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.NRPS = (*NRPSService)(nil)
+
+// maxPages bounds how many rel="next" pages one call follows.
+const maxPages = 1000
+
+type NRPSService struct {
+	registry   lti_ports.Registry
+	tokens     lti_ports.ServiceTokenProvider
+	signer     lti_ports.AsymetricSigner
+	httpClient *http.Client
+	logger     lti_ports.Logger
+}
+
+type membershipContainer struct {
+	ID      string `json:"id"`
+	Context struct {
+		ID    string `json:"id"`
+		Label string `json:"label"`
+		Title string `json:"title"`
+	} `json:"context"`
+	Members []lti_domain.Member `json:"members"`
+}
+
+func (s *NRPSService) GetMemberships(ctx context.Context, session *lti_domain.LTIJWT, filter lti_domain.MembershipFilter) (*lti_domain.Membership, error) {
+	if session.NRPS == nil || session.NRPS.ContextMembershipsURL == "" {
+		return nil, lti_domain.ErrNRPSNotAvailable
+	}
+
+	start, err := url.Parse(session.NRPS.ContextMembershipsURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid context memberships url: %w", err)
+	}
+	q := start.Query()
+	if filter.Role != "" {
+		q.Set("role", filter.Role)
+	}
+	if filter.ResourceLinkID != "" {
+		q.Set("rlid", filter.ResourceLinkID)
+	}
+	if filter.Limit > 0 {
+		q.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	start.RawQuery = q.Encode()
+
+	return s.collect(ctx, session, start.String())
+}
+
+func (s *NRPSService) GetMembershipDifferences(ctx context.Context, session *lti_domain.LTIJWT, differencesURL string) (*lti_domain.Membership, error) {
+	if differencesURL == "" {
+		return nil, fmt.Errorf("differences url is required")
+	}
+	if session.NRPS == nil {
+		return nil, lti_domain.ErrNRPSNotAvailable
+	}
+	return s.collect(ctx, session, differencesURL)
+}
+
+// collect walks every rel="next" page starting at pageURL. The rel="differences"
+// link is only meaningful on the last page, so the last one seen wins.
+func (s *NRPSService) collect(ctx context.Context, session *lti_domain.LTIJWT, pageURL string) (*lti_domain.Membership, error) {
+	if !s.onPlatform(session, pageURL) {
+		return nil, lti_domain.ErrNRPSForeignURL
+	}
+
+	dep, err := s.registry.GetDeployment(ctx, session.ClientID, session.Deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.tokens.AccessToken(ctx, dep, []string{lti_domain.NRPSScope_ContextMembershipReadOnly})
+	if err != nil {
+		s.logger.Error("failed to obtain nrps access token", "deployment", session.Deployment, "error", err)
+		return nil, err
+	}
+
+	out := &lti_domain.Membership{Members: []lti_domain.Member{}}
+	seen := map[string]bool{}
+	for pageURL != "" {
+		if seen[pageURL] {
+			return nil, fmt.Errorf("nrps: next link %s was already fetched", pageURL)
+		}
+		if len(seen) == maxPages {
+			return nil, fmt.Errorf("nrps: more than %d pages", maxPages)
+		}
+		seen[pageURL] = true
+
+		page, links, err := s.fetchPage(ctx, token, pageURL)
+		if err != nil {
+			return nil, err
+		}
+
+		out.ID = page.ID
+		out.ContextID = page.Context.ID
+		out.ContextLabel = page.Context.Label
+		out.ContextTitle = page.Context.Title
+		for _, m := range page.Members {
+			m.Roles = make([]lti_domain.Role, 0, len(m.RoleURIs))
+			for _, uri := range m.RoleURIs {
+				m.Roles = append(m.Roles, lti_domain.ParseRoleURI(uri))
+			}
+			if m.Status == "" {
+				m.Status = lti_domain.MembershipStatus_Active
+			}
+			out.Members = append(out.Members, m)
+		}
+
+		if differences, ok := links["differences"]; ok {
+			out.DifferencesURL = differences
+		}
+		pageURL = links["next"]
+		if pageURL != "" && !s.onPlatform(session, pageURL) {
+			return nil, lti_domain.ErrNRPSForeignURL
+		}
+	}
+
+	return out, nil
+}
+
+// onPlatform reports whether target shares an origin with the session's
+// memberships URL, so the platform's token is never sent to another host.
+func (s *NRPSService) onPlatform(session *lti_domain.LTIJWT, target string) bool {
+	if httplink.SameOrigin(session.NRPS.ContextMembershipsURL, target) {
+		return true
+	}
+	s.logger.Error("refusing nrps request off the platform origin", "url", target)
+	return false
+}
+
+func (s *NRPSService) fetchPage(ctx context.Context, token, pageURL string) (*membershipContainer, map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", lti_domain.NRPSMediaType_MembershipContainer)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		s.logger.Error("nrps request failed", "url", pageURL, "status", resp.StatusCode)
+		return nil, nil, fmt.Errorf("nrps GET %s: unexpected status %d", pageURL, resp.StatusCode)
+	}
+
+	var page membershipContainer
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, nil, fmt.Errorf("decode nrps response: %w", err)
+	}
+
+	return &page, httplink.Parse(resp.Header), nil
+}
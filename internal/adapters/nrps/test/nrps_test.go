@@ -0,0 +1,240 @@
+package nrps_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/crypto"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/nrps"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+type fakePlatform struct {
+	server    *httptest.Server
+	lastQuery url.Values
+}
+
+func newFakePlatform(t *testing.T) *fakePlatform {
+	t.Helper()
+	p := &fakePlatform{}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.FormValue("scope") != lti_domain.NRPSScope_ContextMembershipReadOnly {
+			http.Error(w, "bad scope", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "nrps-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	})
+
+	mux.HandleFunc("GET /memberships", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer nrps-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("Accept") != lti_domain.NRPSMediaType_MembershipContainer {
+			http.Error(w, "bad accept", http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Type", lti_domain.NRPSMediaType_MembershipContainer)
+
+		switch {
+		case r.URL.Query().Get("since") != "":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      p.server.URL + "/memberships",
+				"context": map[string]any{"id": "ctx-1"},
+				"members": []map[string]any{
+					{"user_id": "user-2", "status": "Deleted", "roles": []string{"http://purl.imsglobal.org/vocab/lis/v2/membership#Learner"}},
+				},
+			})
+		case r.URL.Query().Get("page") == "":
+			p.lastQuery = r.URL.Query()
+			w.Header().Set("Link", `<`+p.server.URL+`/memberships?page=2>; rel="next"`)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      p.server.URL + "/memberships",
+				"context": map[string]any{"id": "ctx-1", "label": "BIO101", "title": "Biology"},
+				"members": []map[string]any{
+					{"user_id": "user-1", "name": "Ada", "roles": []string{"http://purl.imsglobal.org/vocab/lis/v2/membership#Instructor"}},
+				},
+			})
+		default:
+			w.Header().Set("Link", `<`+p.server.URL+`/memberships?since=abc>; rel="differences"`)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      p.server.URL + "/memberships",
+				"context": map[string]any{"id": "ctx-1", "label": "BIO101", "title": "Biology"},
+				"members": []map[string]any{
+					{"user_id": "user-2", "status": "Active", "roles": []string{"http://purl.imsglobal.org/vocab/lis/v2/membership#Learner"}},
+				},
+			})
+		}
+	})
+
+	// Misbehaving platforms: a next link back to the same page and one to
+	// another host.
+	mux.HandleFunc("GET /loop", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<`+p.server.URL+`/loop>; rel="next"`)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": p.server.URL + "/loop"})
+	})
+	mux.HandleFunc("GET /leak", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<https://attacker.example/memberships>; rel="next"`)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": p.server.URL + "/leak"})
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func setupNRPS(t *testing.T) (lti_ports.NRPS, *fakePlatform, *lti_domain.LTIJWT) {
+	t.Helper()
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	signer := crypto.NewRS256("tool-key", priv, &priv.PublicKey, "https://tool.example")
+
+	platform := newFakePlatform(t)
+
+	reg := &lti_testadapters.FakeRegistry{}
+	reg.AddDeploymentQuick("client1", "dep1", platform.server.URL, platform.server.URL+"/jwks", "tenantA")
+
+	client := nrps.NewNRPS(nrps.WithRegistry(reg), nrps.WithSigner(signer))
+
+	session := &lti_domain.LTIJWT{
+		ClientID:   "client1",
+		Deployment: "dep1",
+		NRPS: &lti_domain.LTIJWT_NRPS{
+			ContextMembershipsURL: platform.server.URL + "/memberships",
+			ServiceVersions:       []string{"2.0"},
+		},
+	}
+	return client, platform, session
+}
+
+func TestGetMemberships_FollowsNextLink(t *testing.T) {
+	client, _, session := setupNRPS(t)
+
+	membership, err := client.GetMemberships(context.Background(), session, lti_domain.MembershipFilter{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(membership.Members) != 2 {
+		t.Fatalf("expected 2 members across pages, got %d", len(membership.Members))
+	}
+	if membership.ContextTitle != "Biology" {
+		t.Errorf("expected context title %q, got %q", "Biology", membership.ContextTitle)
+	}
+	if membership.Members[0].Status != lti_domain.MembershipStatus_Active {
+		t.Errorf("expected missing status to default to Active, got %q", membership.Members[0].Status)
+	}
+	if !strings.Contains(membership.DifferencesURL, "since=abc") {
+		t.Errorf("expected differences url from last page, got %q", membership.DifferencesURL)
+	}
+}
+
+func TestGetMemberships_MapsRoles(t *testing.T) {
+	client, _, session := setupNRPS(t)
+
+	membership, err := client.GetMemberships(context.Background(), session, lti_domain.MembershipFilter{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(membership.Members[0].Roles) != 1 || membership.Members[0].Roles[0] != lti_domain.MEMBERSHIP_INSTRUCTOR {
+		t.Errorf("expected MEMBERSHIP_INSTRUCTOR, got %v", membership.Members[0].Roles)
+	}
+}
+
+func TestGetMemberships_SendsFilters(t *testing.T) {
+	client, platform, session := setupNRPS(t)
+
+	_, err := client.GetMemberships(context.Background(), session, lti_domain.MembershipFilter{
+		Role:           "http://purl.imsglobal.org/vocab/lis/v2/membership#Learner",
+		ResourceLinkID: "rl-1",
+		Limit:          50,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if platform.lastQuery.Get("role") != "http://purl.imsglobal.org/vocab/lis/v2/membership#Learner" {
+		t.Errorf("expected role filter, got %q", platform.lastQuery.Get("role"))
+	}
+	if platform.lastQuery.Get("rlid") != "rl-1" {
+		t.Errorf("expected rlid filter, got %q", platform.lastQuery.Get("rlid"))
+	}
+	if platform.lastQuery.Get("limit") != "50" {
+		t.Errorf("expected limit 50, got %q", platform.lastQuery.Get("limit"))
+	}
+}
+
+func TestGetMembershipDifferences(t *testing.T) {
+	client, _, session := setupNRPS(t)
+
+	full, err := client.GetMemberships(context.Background(), session, lti_domain.MembershipFilter{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	diff, err := client.GetMembershipDifferences(context.Background(), session, full.DifferencesURL)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(diff.Members) != 1 || diff.Members[0].Status != lti_domain.MembershipStatus_Deleted {
+		t.Fatalf("expected one deleted member, got %+v", diff.Members)
+	}
+}
+
+func TestGetMemberships_NoNRPSClaim(t *testing.T) {
+	client, _, session := setupNRPS(t)
+	session.NRPS = nil
+
+	_, err := client.GetMemberships(context.Background(), session, lti_domain.MembershipFilter{})
+	if !errors.Is(err, lti_domain.ErrNRPSNotAvailable) {
+		t.Fatalf("expected ErrNRPSNotAvailable, got %v", err)
+	}
+}
+
+func TestGetMembershipDifferences_RejectsForeignURL(t *testing.T) {
+	client, _, session := setupNRPS(t)
+
+	_, err := client.GetMembershipDifferences(context.Background(), session, "https://attacker.example/memberships?since=abc")
+	if !errors.Is(err, lti_domain.ErrNRPSForeignURL) {
+		t.Fatalf("expected ErrNRPSForeignURL, got %v", err)
+	}
+}
+
+func TestGetMemberships_NextLinkChecks(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr error
+	}{
+		{"/loop", nil},
+		{"/leak", lti_domain.ErrNRPSForeignURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			client, platform, session := setupNRPS(t)
+			session.NRPS.ContextMembershipsURL = platform.server.URL + tt.path
+
+			_, err := client.GetMemberships(context.Background(), session, lti_domain.MembershipFilter{})
+			if err == nil {
+				t.Fatal("expected the walk to stop with an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package platform_storage implements the tool side of the LTI Client Side
+// postMessages spec: values are written to and read back from a frame owned
+// by the platform, so a launch can survive browsers that block third-party
+// cookies.
+package platform_storage
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+//go:embed html/put_data.html
+var putDataHTML string
+
+//go:embed html/get_data.html
+var getDataHTML string
+
+var (
+	putDataTemplate = template.Must(template.New("put_data").Parse(putDataHTML))
+	getDataTemplate = template.Must(template.New("get_data").Parse(getDataHTML))
+)
+
+// DefaultTimeout is how long the pages wait for the platform to answer
+// before carrying on without it.
+const DefaultTimeout = 2 * time.Second
+
+// Item is a single lti.put_data request.
+type Item struct {
+	MessageID string `json:"message_id"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+}
+
+// NewItem builds an Item with a fresh message ID.
+func NewItem(key, value string) Item {
+	return Item{MessageID: rand.Text(), Key: key, Value: value}
+}
+
+// Origin returns the scheme and host of rawURL, the origin platform storage
+// messages are exchanged with. It returns "" if rawURL has no host.
+func Origin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// PutAndRedirect renders a page that stores items in the platform's storage
+// frame and then navigates to redirect.
+func PutAndRedirect(w http.ResponseWriter, target, origin string, items []Item, redirect string) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	return putDataTemplate.Execute(w, struct {
+		Target    string
+		Origin    string
+		Items     []Item
+		Redirect  string
+		TimeoutMS int64
+	}{target, origin, items, redirect, DefaultTimeout.Milliseconds()})
+}
+
+// Lookup is a single lti.get_data request whose answer is posted back in
+// Field.
+type Lookup struct {
+	MessageID string `json:"message_id"`
+	Key       string `json:"key"`
+	Field     string `json:"field"`
+}
+
+// NewLookup builds a Lookup with a fresh message ID.
+func NewLookup(key, field string) Lookup {
+	return Lookup{MessageID: rand.Text(), Key: key, Field: field}
+}
+
+// GetAndPost renders a page that reads each lookup from the platform's storage
+// frame and POSTs fields to action with the answers added. A field is empty
+// when the platform doesn't answer.
+func GetAndPost(w http.ResponseWriter, target, origin, action string, fields map[string]string, lookups []Lookup) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	return getDataTemplate.Execute(w, struct {
+		Target    string
+		Origin    string
+		Action    string
+		Fields    map[string]string
+		Lookups   []Lookup
+		TimeoutMS int64
+	}{target, origin, action, fields, lookups, DefaultTimeout.Milliseconds()})
+}
@@ -3,7 +3,6 @@ package redirector
 import (
 	"net/http"
 	"net/url"
-	"os"
 
 	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
@@ -16,7 +15,8 @@ type defaultRedirector struct {
 }
 
 func (rw *defaultRedirector) RedirectAfterLaunch(w http.ResponseWriter, r *http.Request, swapToken string) {
-	next, err := url.Parse("/lti/1.3/swap")
+	swapPath := lti_domain.RoutesFromContext(r.Context()).Versioned(lti_domain.LTIVersionFromContext(r.Context()), "swap")
+	next, err := url.Parse(swapPath)
 	if err != nil {
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
@@ -25,19 +25,7 @@ func (rw *defaultRedirector) RedirectAfterLaunch(w http.ResponseWriter, r *http.
 	q.Set("code", swapToken)
 	next.RawQuery = q.Encode()
 
-	useSecureCookie := true
-	if os.Getenv("INSECURE_COOKIES") == "true" {
-		useSecureCookie = false
-	}
-
-	cookie := &http.Cookie{
-		Name:     lti_domain.ContextKey_CookieConfirmation,
-		Value:    swapToken,
-		Path:     "/lti/1.3/swap",
-		HttpOnly: true,
-		Secure:   useSecureCookie,
-		SameSite: http.SameSiteNoneMode,
-	}
+	cookie := lti_domain.CookiePolicyFromContext(r.Context()).Cookie(lti_domain.ContextKey_CookieConfirmation, swapToken, swapPath)
 	http.SetCookie(w, cookie)
 	http.Redirect(w, r, next.String(), http.StatusFound)
 }
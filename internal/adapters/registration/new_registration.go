@@ -0,0 +1,85 @@
+package registration
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_logger"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+func NewDynamicRegistration(opts ...lti_ports.DynamicRegistrationOption) lti_ports.DynamicRegistration {
+	s := &RegistrationService{
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		logger:     lti_logger.NewNoopLogger(),
+		clientName: "LTI Tool",
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.registry == nil {
+		panic("a registry is required for dynamic registration. Call with WithRegistry")
+	}
+
+	if s.tenantResolver == nil {
+		panic("a tenant resolver is required for dynamic registration. Call with WithTenantResolver")
+	}
+
+	return s
+}
+
+func WithRegistry(registry lti_ports.Registry) lti_ports.DynamicRegistrationOption {
+	return func(d lti_ports.DynamicRegistration) {
+		cast := d.(*RegistrationService)
+		cast.registry = registry
+	}
+}
+
+// WithClientName sets the tool name shown to platform administrators.
+func WithClientName(name string) lti_ports.DynamicRegistrationOption {
+	return func(d lti_ports.DynamicRegistration) {
+		cast := d.(*RegistrationService)
+		cast.clientName = name
+	}
+}
+
+// WithScopes requests LTI Advantage scopes (e.g. AGS, NRPS) during registration.
+func WithScopes(scopes ...string) lti_ports.DynamicRegistrationOption {
+	return func(d lti_ports.DynamicRegistration) {
+		cast := d.(*RegistrationService)
+		cast.scopes = append(cast.scopes, scopes...)
+	}
+}
+
+// WithTenantResolver authorizes registrations and assigns the deployment to a
+// tenant. Returning an error or an empty tenant rejects the registration.
+func WithTenantResolver(resolver TenantResolver) lti_ports.DynamicRegistrationOption {
+	return func(d lti_ports.DynamicRegistration) {
+		cast := d.(*RegistrationService)
+		cast.tenantResolver = resolver
+	}
+}
+
+func WithHTTPClient(client *http.Client) lti_ports.DynamicRegistrationOption {
+	return func(d lti_ports.DynamicRegistration) {
+		cast := d.(*RegistrationService)
+		cast.httpClient = client
+	}
+}
+
+func WithLogger(logger lti_ports.Logger) lti_ports.DynamicRegistrationOption {
+	return func(d lti_ports.DynamicRegistration) {
+		cast := d.(*RegistrationService)
+		cast.logger = logger
+	}
+}
+
+// WithAllowedIssuers only accepts registrations from platforms whose issuer
+// has the same origin as one of issuers.
+func WithAllowedIssuers(issuers ...string) lti_ports.DynamicRegistrationOption {
+	return func(d lti_ports.DynamicRegistration) {
+		cast := d.(*RegistrationService)
+		cast.allowedIssuers = append(cast.allowedIssuers, issuers...)
+	}
+}
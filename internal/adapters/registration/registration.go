@@ -0,0 +1,293 @@
+package registration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/httplink"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.DynamicRegistration = (*RegistrationService)(nil)
+
+// TenantResolver authorizes a registration and picks the tenant the
+// deployment belongs to. Returning an error or an empty tenant rejects it.
+type TenantResolver func(r *http.Request, platform lti_domain.PlatformConfiguration) (string, error)
+
+// maxResponseSize caps the platform documents read during registration.
+const maxResponseSize = 1 << 20
+
+type RegistrationService struct {
+	registry       lti_ports.Registry
+	httpClient     *http.Client
+	logger         lti_ports.Logger
+	clientName     string
+	scopes         []string
+	tenantResolver TenantResolver
+	allowedIssuers []string
+}
+
+const toolConfigurationClaim = "https://purl.imsglobal.org/spec/lti-tool-configuration"
+
+type toolConfiguration struct {
+	Domain        string              `json:"domain"`
+	TargetLinkURI string              `json:"target_link_uri"`
+	Claims        []string            `json:"claims"`
+	Messages      []toolMessageConfig `json:"messages"`
+	DeploymentID  string              `json:"deployment_id,omitempty"`
+}
+
+type toolMessageConfig struct {
+	Type      string `json:"type"`
+	TargetURI string `json:"target_link_uri,omitempty"`
+}
+
+type clientRegistration struct {
+	ApplicationType         string            `json:"application_type"`
+	ResponseTypes           []string          `json:"response_types"`
+	GrantTypes              []string          `json:"grant_types"`
+	InitiateLoginURI        string            `json:"initiate_login_uri"`
+	RedirectURIs            []string          `json:"redirect_uris"`
+	ClientName              string            `json:"client_name"`
+	JWKSURI                 string            `json:"jwks_uri"`
+	TokenEndpointAuthMethod string            `json:"token_endpoint_auth_method"`
+	Scope                   string            `json:"scope,omitempty"`
+	ToolConfiguration       toolConfiguration `json:"https://purl.imsglobal.org/spec/lti-tool-configuration"`
+}
+
+type clientRegistrationResponse struct {
+	ClientID          string            `json:"client_id"`
+	ToolConfiguration toolConfiguration `json:"https://purl.imsglobal.org/spec/lti-tool-configuration"`
+}
+
+// closeTemplate tells the platform the registration finished so it can close
+// the registration window.
+var closeTemplate = template.Must(template.New("close").Parse(`<!doctype html>
+<html>
+<body>
+<p>Registration complete. You may close this window.</p>
+<script>(window.opener || window.parent).postMessage({subject: "org.imsglobal.lti.close"}, "*");</script>
+</body>
+</html>`))
+
+func (s *RegistrationService) HandleRegistration(w http.ResponseWriter, r *http.Request, tool lti_domain.ToolRegistration) {
+	configURL := r.URL.Query().Get("openid_configuration")
+	registrationToken := r.URL.Query().Get("registration_token")
+	if configURL == "" {
+		http.Error(w, "missing openid_configuration", http.StatusBadRequest)
+		return
+	}
+	if !s.allowsConfigurationURL(configURL) {
+		s.logger.Warn("registration from disallowed openid configuration", "url", configURL)
+		http.Error(w, "registration not allowed", http.StatusForbidden)
+		return
+	}
+
+	platform, err := s.fetchPlatformConfiguration(r.Context(), configURL, registrationToken)
+	if err != nil {
+		s.logger.Error("failed to fetch platform configuration", "url", configURL, "error", err)
+		http.Error(w, "failed to fetch platform configuration", http.StatusBadGateway)
+		return
+	}
+
+	tenantID, err := s.tenantResolver(r, *platform)
+	if err == nil && tenantID == "" {
+		err = errors.New("no tenant resolved")
+	}
+	if err != nil {
+		s.logger.Warn("tenant resolution rejected registration", "issuer", platform.Issuer, "error", err)
+		http.Error(w, "registration not allowed", http.StatusForbidden)
+		return
+	}
+
+	registered, err := s.register(r.Context(), platform, registrationToken, tool)
+	if err != nil {
+		s.logger.Error("failed to register with platform", "issuer", platform.Issuer, "error", err)
+		http.Error(w, "registration with platform failed", http.StatusBadGateway)
+		return
+	}
+
+	dep := lti_domain.BaseLTIDeployment{
+		InternalID:    uuid.NewString(),
+		ForTenantID:   tenantID,
+		Issuer:        platform.Issuer,
+		ClientID:      registered.ClientID,
+		JWKSURL:       platform.JWKSURI,
+		AuthEndpoint:  platform.AuthorizationEndpoint,
+		TokenEndpoint: platform.TokenEndpoint,
+		DeploymentID:  registered.ToolConfiguration.DeploymentID,
+	}
+
+	existing, err := s.registry.GetDeployment(r.Context(), dep.ClientID, dep.DeploymentID)
+	switch {
+	case err == nil:
+		// Only the platform that owns a deployment may re-register it.
+		base, ok := asBase(existing)
+		if !ok || base.Issuer != platform.Issuer {
+			s.logger.Warn("registration collides with an existing deployment", "issuer", platform.Issuer, "existingIssuer", existing.GetLTIIssuer(), "clientID", dep.ClientID, "deploymentID", dep.DeploymentID)
+			http.Error(w, "deployment already registered", http.StatusConflict)
+			return
+		}
+		// Refresh the endpoints but keep our internal ID and tenant.
+		dep.InternalID = base.InternalID
+		dep.ForTenantID = base.ForTenantID
+		err = s.registry.UpdateDeployment(r.Context(), dep)
+	case errors.Is(err, lti_domain.ErrDeploymentNotFound):
+		err = s.registry.AddDeployment(r.Context(), dep)
+	}
+	if err != nil {
+		s.logger.Error("failed to persist registered deployment", "clientID", dep.ClientID, "deploymentID", dep.DeploymentID, "error", err)
+		http.Error(w, "failed to save deployment", http.StatusInternalServerError)
+		return
+	}
+
+	s.logger.Info("registered with platform", "issuer", platform.Issuer, "clientID", dep.ClientID, "deploymentID", dep.DeploymentID)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = closeTemplate.Execute(w, nil)
+}
+
+func asBase(dep lti_domain.Deployment) (lti_domain.BaseLTIDeployment, bool) {
+	switch d := dep.(type) {
+	case lti_domain.BaseLTIDeployment:
+		return d, true
+	case *lti_domain.BaseLTIDeployment:
+		return *d, true
+	}
+	return lti_domain.BaseLTIDeployment{}, false
+}
+
+func (s *RegistrationService) fetchPlatformConfiguration(ctx context.Context, configURL, registrationToken string) (*lti_domain.PlatformConfiguration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, configURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if registrationToken != "" {
+		req.Header.Set("Authorization", "Bearer "+registrationToken)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: openid configuration returned status %d", lti_domain.ErrRegistrationFailed, resp.StatusCode)
+	}
+
+	var platform lti_domain.PlatformConfiguration
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&platform); err != nil {
+		return nil, fmt.Errorf("decode openid configuration: %w", err)
+	}
+
+	// The issuer must own the configuration URL, otherwise any page could
+	// point us at a look-alike platform.
+	if platform.Issuer == "" || !httplink.SameOrigin(configURL, platform.Issuer) || !s.allowsIssuer(platform.Issuer) {
+		return nil, fmt.Errorf("%w: issuer %q does not match configuration url", lti_domain.ErrRegistrationFailed, platform.Issuer)
+	}
+	if platform.RegistrationEndpoint == "" || platform.JWKSURI == "" || platform.AuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("%w: openid configuration is missing required endpoints", lti_domain.ErrRegistrationFailed)
+	}
+
+	return &platform, nil
+}
+
+func (s *RegistrationService) register(ctx context.Context, platform *lti_domain.PlatformConfiguration, registrationToken string, tool lti_domain.ToolRegistration) (*clientRegistrationResponse, error) {
+	base, err := url.Parse(tool.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tool base url: %w", err)
+	}
+
+	messages := make([]toolMessageConfig, 0, len(tool.Messages))
+	for _, m := range tool.Messages {
+		messages = append(messages, toolMessageConfig{Type: string(m)})
+	}
+
+	body := clientRegistration{
+		ApplicationType:         "web",
+		ResponseTypes:           []string{"id_token"},
+		GrantTypes:              []string{"implicit", "client_credentials"},
+		InitiateLoginURI:        tool.OIDCLoginURL,
+		RedirectURIs:            []string{tool.LaunchURL},
+		ClientName:              s.clientName,
+		JWKSURI:                 tool.JWKSURL,
+		TokenEndpointAuthMethod: "private_key_jwt",
+		Scope:                   strings.Join(s.scopes, " "),
+		ToolConfiguration: toolConfiguration{
+			Domain:        base.Host,
+			TargetLinkURI: tool.LaunchURL,
+			Claims:        []string{"iss", "sub", "name", "given_name", "family_name", "email"},
+			Messages:      messages,
+		},
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, platform.RegistrationEndpoint, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if registrationToken != "" {
+		req.Header.Set("Authorization", "Bearer "+registrationToken)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%w: registration endpoint returned status %d: %s", lti_domain.ErrRegistrationFailed, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var out clientRegistrationResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode registration response: %w", err)
+	}
+	if out.ClientID == "" || out.ToolConfiguration.DeploymentID == "" {
+		return nil, fmt.Errorf("%w: platform did not return a client_id and deployment_id", lti_domain.ErrRegistrationFailed)
+	}
+	return &out, nil
+}
+
+// allowsConfigurationURL reports whether the platform configuration may be
+// fetched from configURL: over https, and from an allowed issuer when
+// WithAllowedIssuers is set.
+func (s *RegistrationService) allowsConfigurationURL(configURL string) bool {
+	u, err := url.Parse(configURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return false
+	}
+	return s.allowsIssuer(configURL)
+}
+
+func (s *RegistrationService) allowsIssuer(uri string) bool {
+	if len(s.allowedIssuers) == 0 {
+		return true
+	}
+	for _, issuer := range s.allowedIssuers {
+		if httplink.SameOrigin(uri, issuer) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+package registration_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/crypto"
+	launcher1dot3 "github.com/vizdos-enterprises/go-lti/internal/adapters/launcher/lti1.3"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/registration"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/server"
+	"github.com/vizdos-enterprises/go-lti/internal/demo_lms"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+func setupRegistration(t *testing.T, extra ...lti_ports.DynamicRegistrationOption) (http.Handler, *demo_lms.DemoPlatform, *httptest.Server, *lti_testadapters.FakeRegistry) {
+	t.Helper()
+	return setupRegistrationFor(t, func(string) []lti_ports.DynamicRegistrationOption { return extra })
+}
+
+// setupRegistrationFor is setupRegistration with options that depend on the
+// platform's URL.
+func setupRegistrationFor(t *testing.T, extra func(lmsURL string) []lti_ports.DynamicRegistrationOption) (http.Handler, *demo_lms.DemoPlatform, *httptest.Server, *lti_testadapters.FakeRegistry) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	lms := httptest.NewTLSServer(mux)
+	t.Cleanup(lms.Close)
+
+	platform := demo_lms.NewDemoPlatform(lms.URL)
+	platform.RegistrationToken = "reg-token"
+	mux.Handle("/", platform.Routes("https://tool.example"))
+
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	signer := crypto.NewRS256("tool-key", priv, &priv.PublicKey, "https://tool.example")
+
+	reg := &lti_testadapters.FakeRegistry{}
+	launcher := launcher1dot3.NewLauncher(
+		launcher1dot3.WithBaseURL("https://tool.example"),
+		launcher1dot3.WithRegistry(reg),
+		launcher1dot3.WithEphemeralStorage(reg),
+		launcher1dot3.WithSigner(signer),
+	)
+
+	opts := append([]lti_ports.DynamicRegistrationOption{
+		registration.WithRegistry(reg),
+		registration.WithClientName("Demo Tool"),
+		registration.WithScopes(lti_domain.AGSScope_Score),
+		registration.WithHTTPClient(lms.Client()),
+		registration.WithTenantResolver(func(*http.Request, lti_domain.PlatformConfiguration) (string, error) {
+			return "tenant-default", nil
+		}),
+	}, extra(lms.URL)...)
+
+	srv := server.NewServer(
+		server.WithLauncher(launcher),
+		server.WithVerifier(signer),
+		server.WithDynamicRegistration(registration.NewDynamicRegistration(opts...)),
+	)
+	return srv.CreateRoutes(), platform, lms, reg
+}
+
+func startRegistration(h http.Handler, lms *httptest.Server, token string) *httptest.ResponseRecorder {
+	q := url.Values{
+		"openid_configuration": {lms.URL + "/.well-known/openid-configuration"},
+		"registration_token":   {token},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/lti/register?"+q.Encode(), nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestDynamicRegistration_AgainstDemoPlatform(t *testing.T) {
+	h, platform, lms, reg := setupRegistration(t)
+
+	w := startRegistration(h, lms, "reg-token")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "org.imsglobal.lti.close") {
+		t.Errorf("expected close message in response")
+	}
+
+	dep := reg.MustGetDeploymentT(t, platform.DeploymentID)
+	if dep.GetLTIClientID() != platform.ClientID {
+		t.Errorf("expected client id %q, got %q", platform.ClientID, dep.GetLTIClientID())
+	}
+	if dep.GetLTIIssuer() != lms.URL {
+		t.Errorf("expected issuer %q, got %q", lms.URL, dep.GetLTIIssuer())
+	}
+	if dep.GetLTIJWKSURL() != platform.JWKSURL() {
+		t.Errorf("expected jwks url %q, got %q", platform.JWKSURL(), dep.GetLTIJWKSURL())
+	}
+	if dep.GetLTIAuthEndpoint() != lms.URL+"/auth" {
+		t.Errorf("expected auth endpoint from openid configuration, got %q", dep.GetLTIAuthEndpoint())
+	}
+
+	tools := platform.RegisteredTools()
+	if len(tools) != 1 {
+		t.Fatalf("expected platform to record one registration, got %d", len(tools))
+	}
+	tool := tools[0]
+	if tool.InitiateLoginURI != "https://tool.example/lti/1.3/oidc" {
+		t.Errorf("unexpected initiate_login_uri %q", tool.InitiateLoginURI)
+	}
+	if !slices.Equal(tool.RedirectURIs, []string{"https://tool.example/lti/1.3/launch"}) {
+		t.Errorf("unexpected redirect_uris %v", tool.RedirectURIs)
+	}
+	if tool.JWKSURI != "https://tool.example/lti/.well-known/jwks.json" {
+		t.Errorf("unexpected jwks_uri %q", tool.JWKSURI)
+	}
+	if !slices.Equal(tool.Messages, []string{string(lti_domain.LTIService_ResourceLink)}) {
+		t.Errorf("expected enabled message types, got %v", tool.Messages)
+	}
+	if tool.Scope != lti_domain.AGSScope_Score {
+		t.Errorf("expected requested scope, got %q", tool.Scope)
+	}
+}
+
+func TestDynamicRegistration_ReRegisterUpdates(t *testing.T) {
+	h, platform, lms, reg := setupRegistration(t)
+
+	if w := startRegistration(h, lms, "reg-token"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	first := reg.MustGetDeploymentT(t, platform.DeploymentID).(lti_domain.BaseLTIDeployment)
+
+	if w := startRegistration(h, lms, "reg-token"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 on re-registration, got %d", w.Code)
+	}
+	second := reg.MustGetDeploymentT(t, platform.DeploymentID).(lti_domain.BaseLTIDeployment)
+	if first.InternalID != second.InternalID {
+		t.Errorf("expected internal id to be preserved, got %q then %q", first.InternalID, second.InternalID)
+	}
+	if second.ForTenantID != "tenant-default" {
+		t.Errorf("expected tenant to be preserved, got %q", second.ForTenantID)
+	}
+}
+
+func TestDynamicRegistration_RejectsCollisionWithOtherIssuer(t *testing.T) {
+	h, platform, lms, reg := setupRegistration(t)
+	reg.AddDeploymentQuick(platform.ClientID, platform.DeploymentID, "https://victim.example", "https://victim.example/jwks", "tenant-victim")
+
+	if w := startRegistration(h, lms, "reg-token"); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+	dep := reg.MustGetDeploymentT(t, platform.DeploymentID)
+	if dep.GetLTIIssuer() != "https://victim.example" || dep.GetTenantID() != "tenant-victim" {
+		t.Errorf("expected the existing deployment to be untouched, got %+v", dep)
+	}
+}
+
+func TestDynamicRegistration_RejectsEmptyTenant(t *testing.T) {
+	h, _, lms, reg := setupRegistration(t, registration.WithTenantResolver(
+		func(*http.Request, lti_domain.PlatformConfiguration) (string, error) { return "", nil },
+	))
+
+	if w := startRegistration(h, lms, "reg-token"); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+	if reg.CountDeployments() != 0 {
+		t.Errorf("expected no deployment to be saved")
+	}
+}
+
+func TestDynamicRegistration_RequiresTenantResolver(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic without a tenant resolver")
+		}
+	}()
+	registration.NewDynamicRegistration(registration.WithRegistry(&lti_testadapters.FakeRegistry{}))
+}
+
+func TestDynamicRegistration_ConfigurationURLChecks(t *testing.T) {
+	lookalike := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/register" {
+			_, _ = w.Write([]byte(`{"client_id":"c","https://purl.imsglobal.org/spec/lti-tool-configuration":{"deployment_id":"d"}}`))
+			return
+		}
+		// The issuer shares a prefix with the configuration URL but not its
+		// origin; otherwise this is a working platform.
+		origin := "https://" + r.Host
+		_, _ = w.Write([]byte(`{"issuer":"https://127.0.0.1","registration_endpoint":"` + origin + `/register","jwks_uri":"` + origin + `/jwks","authorization_endpoint":"` + origin + `/auth"}`))
+	}))
+	t.Cleanup(lookalike.Close)
+
+	tests := []struct {
+		name       string
+		configURL  func(lmsURL string) string
+		allowed    func(lmsURL string) []string
+		wantStatus int
+	}{
+		{"plain http", func(lmsURL string) string {
+			return strings.Replace(lmsURL, "https://", "http://", 1) + "/.well-known/openid-configuration"
+		}, nil, http.StatusForbidden},
+		{"issuer not allowed", func(lmsURL string) string {
+			return lmsURL + "/.well-known/openid-configuration"
+		}, func(string) []string { return []string{"https://lms.example"} }, http.StatusForbidden},
+		{"issuer allowed", func(lmsURL string) string {
+			return lmsURL + "/.well-known/openid-configuration"
+		}, func(lmsURL string) []string { return []string{lmsURL + "/"} }, http.StatusOK},
+		{"issuer from another origin", func(string) string {
+			return lookalike.URL + "/.well-known/openid-configuration"
+		}, nil, http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, lms, _ := setupRegistrationFor(t, func(lmsURL string) []lti_ports.DynamicRegistrationOption {
+				if tt.allowed == nil {
+					return nil
+				}
+				return []lti_ports.DynamicRegistrationOption{registration.WithAllowedIssuers(tt.allowed(lmsURL)...)}
+			})
+
+			q := url.Values{"openid_configuration": {tt.configURL(lms.URL)}, "registration_token": {"reg-token"}}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/lti/register?"+q.Encode(), nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDynamicRegistration_TenantResolver(t *testing.T) {
+	h, platform, lms, reg := setupRegistration(t, registration.WithTenantResolver(
+		func(r *http.Request, p lti_domain.PlatformConfiguration) (string, error) {
+			if p.LTIPlatform.ProductFamilyCode != "demo" {
+				return "", errors.New("unknown platform")
+			}
+			return "tenant-demo", nil
+		},
+	))
+
+	if w := startRegistration(h, lms, "reg-token"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if got := reg.MustGetDeploymentT(t, platform.DeploymentID).GetTenantID(); got != "tenant-demo" {
+		t.Errorf("expected tenant %q, got %v", "tenant-demo", got)
+	}
+}
@@ -3,6 +3,7 @@ package registry
 import (
 	"context"
 	"crypto/rand"
+	"slices"
 	"sync"
 	"time"
 
@@ -24,6 +25,19 @@ type inMemoryRegistry struct {
 	state          map[string]stateRecord
 	swapTokens     map[string]*lti_domain.SwapToken
 	exchangeTokens map[string]*lti_domain.ExchangeToken
+	usedNonces     map[string]time.Time // value: when the nonce may be forgotten
+	revocations    map[string]revocationRecord
+	sessionClaims  map[string]sessionClaimsRecord
+}
+
+type sessionClaimsRecord struct {
+	claims    lti_domain.SessionClaims
+	expiresAt time.Time
+}
+
+type revocationRecord struct {
+	revokedAt time.Time
+	expiresAt time.Time
 }
 
 type stateRecord struct {
@@ -38,6 +52,9 @@ func NewInMemoryRegistry() lti_ports.EphemeralRegistry {
 		state:          make(map[string]stateRecord),
 		swapTokens:     make(map[string]*lti_domain.SwapToken),
 		exchangeTokens: make(map[string]*lti_domain.ExchangeToken),
+		usedNonces:     make(map[string]time.Time),
+		revocations:    make(map[string]revocationRecord),
+		sessionClaims:  make(map[string]sessionClaimsRecord),
 	}
 }
 
@@ -61,6 +78,58 @@ func (r *inMemoryRegistry) GetDeployment(ctx context.Context, clientID, deployme
 	return dep, nil
 }
 
+func (r *inMemoryRegistry) ListDeployments(ctx context.Context, filter lti_domain.DeploymentFilter) ([]lti_domain.Deployment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]string, 0, len(r.deployments))
+	for key, dep := range r.deployments {
+		if filter.Matches(dep) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	out := make([]lti_domain.Deployment, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, r.deployments[key])
+	}
+	return out, nil
+}
+
+func (r *inMemoryRegistry) AddDeployment(ctx context.Context, dep lti_domain.Deployment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := makeDeploymentKey(dep.GetLTIClientID(), dep.GetLTIDeploymentID())
+	r.deployments[key] = dep
+	return nil
+}
+
+func (r *inMemoryRegistry) UpdateDeployment(ctx context.Context, dep lti_domain.Deployment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := makeDeploymentKey(dep.GetLTIClientID(), dep.GetLTIDeploymentID())
+	if _, ok := r.deployments[key]; !ok {
+		return lti_domain.ErrDeploymentNotFound
+	}
+	r.deployments[key] = dep
+	return nil
+}
+
+func (r *inMemoryRegistry) RemoveDeployment(ctx context.Context, clientID, deploymentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := makeDeploymentKey(clientID, deploymentID)
+	if _, ok := r.deployments[key]; !ok {
+		return lti_domain.ErrDeploymentNotFound
+	}
+	delete(r.deployments, key)
+	return nil
+}
+
 // ====================
 //  EphemeralStore interface
 // ====================
@@ -161,13 +230,88 @@ func (r *inMemoryRegistry) GetState(ctx context.Context, stateID string) (*lti_d
 	return &rec.data, nil
 }
 
-// ====================
-//  Helpers for seeding
-// ====================
+func (r *inMemoryRegistry) ConsumeNonce(ctx context.Context, nonce string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for n, until := range r.usedNonces {
+		if now.After(until) {
+			delete(r.usedNonces, n)
+		}
+	}
+
+	if _, ok := r.usedNonces[nonce]; ok {
+		return lti_domain.ErrNonceReplayed
+	}
+	r.usedNonces[nonce] = now.Add(ttl)
+	return nil
+}
 
-func (r *inMemoryRegistry) AddDeployment(ctx context.Context, dep lti_domain.Deployment) {
+func (r *inMemoryRegistry) SaveRevocation(ctx context.Context, key string, revokedAt time.Time, ttl time.Duration) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	key := makeDeploymentKey(dep.GetLTIClientID(), dep.GetLTIDeploymentID())
-	r.deployments[key] = dep
+
+	now := time.Now()
+	for k, rec := range r.revocations {
+		if now.After(rec.expiresAt) {
+			delete(r.revocations, k)
+		}
+	}
+
+	if rec, ok := r.revocations[key]; ok && rec.revokedAt.After(revokedAt) {
+		revokedAt = rec.revokedAt
+	}
+	r.revocations[key] = revocationRecord{
+		revokedAt: revokedAt,
+		expiresAt: now.Add(ttl),
+	}
+	return nil
+}
+
+func (r *inMemoryRegistry) LatestRevocation(ctx context.Context, keys ...string) (time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var latest time.Time
+	for _, key := range keys {
+		rec, ok := r.revocations[key]
+		if !ok || now.After(rec.expiresAt) {
+			continue
+		}
+		if rec.revokedAt.After(latest) {
+			latest = rec.revokedAt
+		}
+	}
+	return latest, nil
+}
+
+func (r *inMemoryRegistry) SaveSessionClaims(ctx context.Context, key string, claims lti_domain.SessionClaims, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for k, rec := range r.sessionClaims {
+		if now.After(rec.expiresAt) {
+			delete(r.sessionClaims, k)
+		}
+	}
+
+	r.sessionClaims[key] = sessionClaimsRecord{
+		claims:    claims,
+		expiresAt: now.Add(ttl),
+	}
+	return nil
+}
+
+func (r *inMemoryRegistry) GetSessionClaims(ctx context.Context, key string) (*lti_domain.SessionClaims, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rec, ok := r.sessionClaims[key]
+	if !ok || time.Now().After(rec.expiresAt) {
+		return nil, lti_domain.ErrSessionClaimsNotFound
+	}
+	return &rec.claims, nil
 }
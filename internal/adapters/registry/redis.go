@@ -0,0 +1,301 @@
+package registry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.EphemeralStore = (*redisStore)(nil)
+
+// redisStore implements EphemeralStore on top of Redis so launches can be
+// served by any replica. Every one-time token is redeemed atomically.
+type redisStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+type RedisOption func(*redisStore)
+
+// WithKeyPrefix namespaces every key written by the store. Defaults to "lti:".
+func WithKeyPrefix(prefix string) RedisOption {
+	return func(s *redisStore) {
+		s.keyPrefix = prefix
+	}
+}
+
+// NewRedisStore creates an EphemeralStore backed by the given Redis client.
+func NewRedisStore(client redis.UniversalClient, opts ...RedisOption) lti_ports.EphemeralStore {
+	if client == nil {
+		panic("a redis client is required for the redis ephemeral store")
+	}
+
+	s := &redisStore{
+		client:    client,
+		keyPrefix: "lti:",
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Exchange tokens are stored as a hash so the claim can flip individual
+// fields inside a script without re-encoding the payload.
+const (
+	exchangeFieldData      = "data"
+	exchangeFieldUntil     = "until"
+	exchangeFieldExchanged = "exchanged"
+	exchangeFieldChallenge = "challenge"
+	exchangeFieldAuthToken = "auth"
+)
+
+const (
+	claimResultNotFound = 0
+	claimResultOK       = 1
+	claimResultUsed     = -1
+	claimResultExpired  = -2
+)
+
+var claimExchangeScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+if redis.call("HGET", KEYS[1], "exchanged") == "1" then
+	return -1
+end
+if tonumber(redis.call("HGET", KEYS[1], "until")) < tonumber(ARGV[1]) then
+	return -2
+end
+redis.call("HSET", KEYS[1], "exchanged", "1", "challenge", ARGV[2], "auth", ARGV[3])
+return 1
+`)
+
+var getAndDeleteHashScript = redis.NewScript(`
+local v = redis.call("HGETALL", KEYS[1])
+if #v == 0 then
+	return false
+end
+redis.call("DEL", KEYS[1])
+return v
+`)
+
+func (s *redisStore) stateKey(id string) string    { return s.keyPrefix + "state:" + id }
+func (s *redisStore) swapKey(id string) string     { return s.keyPrefix + "swap:" + id }
+func (s *redisStore) exchangeKey(id string) string { return s.keyPrefix + "exchange:" + id }
+func (s *redisStore) nonceKey(id string) string    { return s.keyPrefix + "nonce:" + id }
+func (s *redisStore) revokedKey(id string) string  { return s.keyPrefix + "revoked:" + id }
+func (s *redisStore) claimsKey(id string) string   { return s.keyPrefix + "claims:" + id }
+
+func (s *redisStore) SaveState(ctx context.Context, stateID string, data lti_domain.State, ttl time.Duration) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+	return s.client.Set(ctx, s.stateKey(stateID), raw, ttl).Err()
+}
+
+func (s *redisStore) DeleteState(ctx context.Context, stateID string) error {
+	n, err := s.client.Del(ctx, s.stateKey(stateID)).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return lti_domain.ErrStateNotFound
+	}
+	return nil
+}
+
+func (s *redisStore) GetState(ctx context.Context, stateID string) (*lti_domain.State, error) {
+	raw, err := s.client.Get(ctx, s.stateKey(stateID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, lti_domain.ErrStateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state lti_domain.State
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("decode state: %w", err)
+	}
+	return &state, nil
+}
+
+func (s *redisStore) SaveSwapToken(ctx context.Context, swapToken string, data lti_domain.SwapToken, ttl time.Duration) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encode swap token: %w", err)
+	}
+	return s.client.Set(ctx, s.swapKey(swapToken), raw, ttl).Err()
+}
+
+func (s *redisStore) GetAndDeleteSwapToken(ctx context.Context, swapToken string) (*lti_domain.SwapToken, error) {
+	raw, err := s.client.GetDel(ctx, s.swapKey(swapToken)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, lti_domain.ErrSwapTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var swap lti_domain.SwapToken
+	if err := json.Unmarshal(raw, &swap); err != nil {
+		return nil, fmt.Errorf("decode swap token: %w", err)
+	}
+	return &swap, nil
+}
+
+func (s *redisStore) SaveExchangeToken(ctx context.Context, exchangeTokenID string, data lti_domain.ExchangeToken, ttl time.Duration) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encode exchange token: %w", err)
+	}
+
+	exchanged := "0"
+	if data.Exchanged {
+		exchanged = "1"
+	}
+
+	key := s.exchangeKey(exchangeTokenID)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key,
+			exchangeFieldData, raw,
+			exchangeFieldUntil, data.ClaimableUntil.UnixMilli(),
+			exchangeFieldExchanged, exchanged,
+			exchangeFieldChallenge, data.Challenge,
+			exchangeFieldAuthToken, data.AuthToken,
+		)
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *redisStore) ClaimExchangeToken(ctx context.Context, exchangeTokenID string, challenge string) (string, error) {
+	authToken := rand.Text()
+
+	res, err := claimExchangeScript.Run(ctx, s.client,
+		[]string{s.exchangeKey(exchangeTokenID)},
+		time.Now().UTC().UnixMilli(), challenge, authToken,
+	).Int()
+	if err != nil {
+		return "", err
+	}
+
+	switch res {
+	case claimResultOK:
+		return authToken, nil
+	case claimResultUsed:
+		return "", lti_domain.ErrExchangeTokenAlreadyExchanged
+	case claimResultExpired:
+		return "", lti_domain.ErrExchangeRedemptionExpired
+	default:
+		return "", lti_domain.ErrExchangeTokenNotFound
+	}
+}
+
+func (s *redisStore) GetAndDeleteExchangeToken(ctx context.Context, exchangeTokenID string) (*lti_domain.ExchangeToken, error) {
+	fields, err := getAndDeleteHashScript.Run(ctx, s.client, []string{s.exchangeKey(exchangeTokenID)}).StringSlice()
+	if errors.Is(err, redis.Nil) {
+		return nil, lti_domain.ErrExchangeTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hash := make(map[string]string, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		hash[fields[i]] = fields[i+1]
+	}
+
+	var exch lti_domain.ExchangeToken
+	if err := json.Unmarshal([]byte(hash[exchangeFieldData]), &exch); err != nil {
+		return nil, fmt.Errorf("decode exchange token: %w", err)
+	}
+	exch.Exchanged = hash[exchangeFieldExchanged] == "1"
+	exch.Challenge = hash[exchangeFieldChallenge]
+	exch.AuthToken = hash[exchangeFieldAuthToken]
+	return &exch, nil
+}
+
+func (s *redisStore) ConsumeNonce(ctx context.Context, nonce string, ttl time.Duration) error {
+	ok, err := s.client.SetNX(ctx, s.nonceKey(nonce), 1, ttl).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return lti_domain.ErrNonceReplayed
+	}
+	return nil
+}
+
+func (s *redisStore) SaveRevocation(ctx context.Context, key string, revokedAt time.Time, ttl time.Duration) error {
+	return s.client.Set(ctx, s.revokedKey(key), revokedAt.UnixNano(), ttl).Err()
+}
+
+func (s *redisStore) LatestRevocation(ctx context.Context, keys ...string) (time.Time, error) {
+	if len(keys) == 0 {
+		return time.Time{}, nil
+	}
+
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = s.revokedKey(key)
+	}
+	values, err := s.client.MGet(ctx, redisKeys...).Result()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var latest time.Time
+	for _, v := range values {
+		raw, ok := v.(string)
+		if !ok {
+			continue
+		}
+		nanos, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("decode revocation: %w", err)
+		}
+		if at := time.Unix(0, nanos); at.After(latest) {
+			latest = at
+		}
+	}
+	return latest, nil
+}
+
+func (s *redisStore) SaveSessionClaims(ctx context.Context, key string, claims lti_domain.SessionClaims, ttl time.Duration) error {
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return fmt.Errorf("encode session claims: %w", err)
+	}
+	return s.client.Set(ctx, s.claimsKey(key), raw, ttl).Err()
+}
+
+func (s *redisStore) GetSessionClaims(ctx context.Context, key string) (*lti_domain.SessionClaims, error) {
+	raw, err := s.client.Get(ctx, s.claimsKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, lti_domain.ErrSessionClaimsNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var claims lti_domain.SessionClaims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return nil, fmt.Errorf("decode session claims: %w", err)
+	}
+	return &claims, nil
+}
@@ -0,0 +1,264 @@
+package registry
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_logger"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.Registry = (*sqlRegistry)(nil)
+
+// Dialect selects the placeholder style used when talking to the database.
+type Dialect int
+
+const (
+	DialectPostgres Dialect = iota
+	DialectSQLite
+)
+
+// rebind rewrites "?" placeholders into the dialect's native form.
+func (d Dialect) rebind(query string) string {
+	if d != DialectPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// sqlRegistry implements Registry on top of database/sql. Deployments are
+// keyed by (client_id, deployment_id), mirroring the in-memory registry.
+type sqlRegistry struct {
+	db      *sql.DB
+	dialect Dialect
+	logger  lti_ports.Logger
+}
+
+type SQLOption func(*sqlRegistry)
+
+// WithDialect sets the SQL dialect. Defaults to DialectPostgres.
+func WithDialect(dialect Dialect) SQLOption {
+	return func(r *sqlRegistry) {
+		r.dialect = dialect
+	}
+}
+
+// WithSQLLogger sets the logger used for migrations and write failures.
+func WithSQLLogger(logger lti_ports.Logger) SQLOption {
+	return func(r *sqlRegistry) {
+		r.logger = logger
+	}
+}
+
+// NewSQLRegistry creates a Registry backed by db and brings its schema up to
+// date before returning.
+func NewSQLRegistry(ctx context.Context, db *sql.DB, opts ...SQLOption) (lti_ports.Registry, error) {
+	if db == nil {
+		panic("a database handle is required for the sql registry")
+	}
+
+	r := &sqlRegistry{
+		db:      db,
+		dialect: DialectPostgres,
+		logger:  lti_logger.NewNoopLogger(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if err := r.migrate(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+const deploymentColumns = `internal_id, tenant_id, issuer, client_id, jwks_url, auth_endpoint, token_endpoint, deployment_id, disabled`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDeployment(row rowScanner) (lti_domain.BaseLTIDeployment, error) {
+	var dep lti_domain.BaseLTIDeployment
+	err := row.Scan(
+		&dep.InternalID,
+		&dep.ForTenantID,
+		&dep.Issuer,
+		&dep.ClientID,
+		&dep.JWKSURL,
+		&dep.AuthEndpoint,
+		&dep.TokenEndpoint,
+		&dep.DeploymentID,
+		&dep.Disabled,
+	)
+	return dep, err
+}
+
+func (r *sqlRegistry) GetDeployment(ctx context.Context, clientID, deploymentID string) (lti_domain.Deployment, error) {
+	row := r.db.QueryRowContext(ctx, r.dialect.rebind(`
+		SELECT `+deploymentColumns+`
+		FROM lti_deployments
+		WHERE client_id = ? AND deployment_id = ?`),
+		clientID, deploymentID,
+	)
+
+	dep, err := scanDeployment(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, lti_domain.ErrDeploymentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dep, nil
+}
+
+func (r *sqlRegistry) ListDeployments(ctx context.Context, filter lti_domain.DeploymentFilter) ([]lti_domain.Deployment, error) {
+	query := `SELECT ` + deploymentColumns + ` FROM lti_deployments WHERE 1 = 1`
+	args := []any{}
+	if filter.TenantID != nil {
+		query += ` AND tenant_id = ?`
+		args = append(args, tenantIDToString(filter.TenantID))
+	}
+	if filter.Issuer != "" {
+		query += ` AND issuer = ?`
+		args = append(args, filter.Issuer)
+	}
+	query += ` ORDER BY client_id, deployment_id`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []lti_domain.Deployment{}
+	for rows.Next() {
+		dep, err := scanDeployment(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, dep)
+	}
+	return out, rows.Err()
+}
+
+func (r *sqlRegistry) AddDeployment(ctx context.Context, dep lti_domain.Deployment) error {
+	now := time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, r.dialect.rebind(`
+		INSERT INTO lti_deployments
+			(client_id, deployment_id, internal_id, tenant_id, issuer, jwks_url, auth_endpoint, token_endpoint, disabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (client_id, deployment_id) DO UPDATE SET
+			internal_id = excluded.internal_id,
+			tenant_id = excluded.tenant_id,
+			issuer = excluded.issuer,
+			jwks_url = excluded.jwks_url,
+			auth_endpoint = excluded.auth_endpoint,
+			token_endpoint = excluded.token_endpoint,
+			disabled = excluded.disabled,
+			updated_at = excluded.updated_at`),
+		dep.GetLTIClientID(),
+		dep.GetLTIDeploymentID(),
+		internalIDOf(dep),
+		tenantIDToString(dep.GetTenantID()),
+		dep.GetLTIIssuer(),
+		dep.GetLTIJWKSURL(),
+		dep.GetLTIAuthEndpoint(),
+		dep.GetLTITokenEndpoint(),
+		lti_domain.IsDeploymentDisabled(dep),
+		now,
+		now,
+	)
+	if err != nil {
+		r.logger.Error("failed to add deployment", "clientID", dep.GetLTIClientID(), "deploymentID", dep.GetLTIDeploymentID(), "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *sqlRegistry) UpdateDeployment(ctx context.Context, dep lti_domain.Deployment) error {
+	res, err := r.db.ExecContext(ctx, r.dialect.rebind(`
+		UPDATE lti_deployments SET
+			internal_id = ?,
+			tenant_id = ?,
+			issuer = ?,
+			jwks_url = ?,
+			auth_endpoint = ?,
+			token_endpoint = ?,
+			disabled = ?,
+			updated_at = ?
+		WHERE client_id = ? AND deployment_id = ?`),
+		internalIDOf(dep),
+		tenantIDToString(dep.GetTenantID()),
+		dep.GetLTIIssuer(),
+		dep.GetLTIJWKSURL(),
+		dep.GetLTIAuthEndpoint(),
+		dep.GetLTITokenEndpoint(),
+		lti_domain.IsDeploymentDisabled(dep),
+		time.Now().UTC(),
+		dep.GetLTIClientID(),
+		dep.GetLTIDeploymentID(),
+	)
+	if err != nil {
+		r.logger.Error("failed to update deployment", "clientID", dep.GetLTIClientID(), "deploymentID", dep.GetLTIDeploymentID(), "error", err)
+		return err
+	}
+	return expectOneRow(res, lti_domain.ErrDeploymentNotFound)
+}
+
+func (r *sqlRegistry) RemoveDeployment(ctx context.Context, clientID, deploymentID string) error {
+	res, err := r.db.ExecContext(ctx, r.dialect.rebind(`
+		DELETE FROM lti_deployments WHERE client_id = ? AND deployment_id = ?`),
+		clientID, deploymentID,
+	)
+	if err != nil {
+		r.logger.Error("failed to remove deployment", "clientID", clientID, "deploymentID", deploymentID, "error", err)
+		return err
+	}
+	return expectOneRow(res, lti_domain.ErrDeploymentNotFound)
+}
+
+// expectOneRow returns notAffected when the statement touched no rows.
+func expectOneRow(res sql.Result, notAffected error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notAffected
+	}
+	return nil
+}
+
+func internalIDOf(dep lti_domain.Deployment) string {
+	switch d := dep.(type) {
+	case lti_domain.BaseLTIDeployment:
+		return d.InternalID
+	case *lti_domain.BaseLTIDeployment:
+		return d.InternalID
+	}
+	return dep.GetDeploymentID()
+}
+
+func tenantIDToString(id lti_domain.TenantID) string {
+	if id == nil {
+		return ""
+	}
+	return lti_domain.TenantIDString(id)
+}
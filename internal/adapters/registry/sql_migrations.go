@@ -0,0 +1,75 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// sqlMigrations are applied in order and recorded by version. Never edit an
+// entry once released; append a new one instead.
+var sqlMigrations = []string{
+	// 1: deployments
+	`CREATE TABLE IF NOT EXISTS lti_deployments (
+		client_id      VARCHAR(255) NOT NULL,
+		deployment_id  VARCHAR(255) NOT NULL,
+		internal_id    VARCHAR(255) NOT NULL DEFAULT '',
+		tenant_id      VARCHAR(255) NOT NULL DEFAULT '',
+		issuer         TEXT NOT NULL,
+		jwks_url       TEXT NOT NULL,
+		auth_endpoint  TEXT NOT NULL,
+		token_endpoint TEXT NOT NULL DEFAULT '',
+		created_at     TIMESTAMP NOT NULL,
+		updated_at     TIMESTAMP NOT NULL,
+		PRIMARY KEY (client_id, deployment_id)
+	)`,
+	// 2: disabled flag
+	`ALTER TABLE lti_deployments ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE`,
+	// 3: lookups by tenant and issuer
+	`CREATE INDEX IF NOT EXISTS lti_deployments_tenant_issuer ON lti_deployments (tenant_id, issuer)`,
+}
+
+func (r *sqlRegistry) migrate(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS lti_schema_migrations (
+		version    INTEGER NOT NULL PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("create migrations table: %w", err)
+	}
+
+	var current int
+	err = r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM lti_schema_migrations`).Scan(&current)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+
+	for i := current; i < len(sqlMigrations); i++ {
+		version := i + 1
+		if err := r.applyMigration(ctx, version, sqlMigrations[i]); err != nil {
+			return fmt.Errorf("apply migration %d: %w", version, err)
+		}
+		r.logger.Info("applied registry migration", "version", version)
+	}
+	return nil
+}
+
+func (r *sqlRegistry) applyMigration(ctx context.Context, version int, stmt string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, stmt); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind(`INSERT INTO lti_schema_migrations (version, applied_at) VALUES (?, ?)`),
+		version, time.Now().UTC(),
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,148 @@
+package registry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/registry"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+func TestMemoryRegistry_DeploymentLifecycle(t *testing.T) {
+	reg := registry.NewInMemoryRegistry()
+	ctx := context.Background()
+
+	if err := reg.AddDeployment(ctx, sampleDeployment()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	replaced := sampleDeployment()
+	replaced.JWKSURL = "https://lms.example/jwks-v2"
+	if err := reg.AddDeployment(ctx, replaced); err != nil {
+		t.Fatalf("expected add to replace, got %v", err)
+	}
+	if dep, _ := reg.GetDeployment(ctx, "client1", "dep1"); dep.GetLTIJWKSURL() != "https://lms.example/jwks-v2" {
+		t.Errorf("expected replaced jwks url, got %q", dep.GetLTIJWKSURL())
+	}
+
+	updated := sampleDeployment()
+	updated.Disabled = true
+	if err := reg.UpdateDeployment(ctx, updated); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	dep, _ := reg.GetDeployment(ctx, "client1", "dep1")
+	if !lti_domain.IsDeploymentDisabled(dep) {
+		t.Errorf("expected deployment to be disabled")
+	}
+
+	if err := reg.RemoveDeployment(ctx, "client1", "dep1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := reg.UpdateDeployment(ctx, updated); !errors.Is(err, lti_domain.ErrDeploymentNotFound) {
+		t.Fatalf("expected ErrDeploymentNotFound after remove, got %v", err)
+	}
+}
+
+func TestMemoryRegistry_ListFilters(t *testing.T) {
+	reg := registry.NewInMemoryRegistry()
+	ctx := context.Background()
+
+	a := sampleDeployment()
+	b := sampleDeployment()
+	b.DeploymentID, b.ForTenantID = "dep2", "tenantB"
+	_ = reg.AddDeployment(ctx, a)
+	_ = reg.AddDeployment(ctx, b)
+
+	deps, err := reg.ListDeployments(ctx, lti_domain.DeploymentFilter{TenantID: "tenantB"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(deps) != 1 || deps[0].GetLTIDeploymentID() != "dep2" {
+		t.Errorf("expected only dep2, got %v", deps)
+	}
+
+	all, _ := reg.ListDeployments(ctx, lti_domain.DeploymentFilter{Issuer: "https://lms.example"})
+	if len(all) != 2 {
+		t.Errorf("expected 2 deployments for issuer, got %d", len(all))
+	}
+}
+
+func TestMemoryRegistry_ConsumeNonce(t *testing.T) {
+	reg := registry.NewInMemoryRegistry()
+	ctx := context.Background()
+
+	if err := reg.ConsumeNonce(ctx, "n1", time.Minute); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := reg.ConsumeNonce(ctx, "n1", time.Minute); !errors.Is(err, lti_domain.ErrNonceReplayed) {
+		t.Fatalf("expected ErrNonceReplayed, got %v", err)
+	}
+	if err := reg.ConsumeNonce(ctx, "n2", time.Minute); err != nil {
+		t.Fatalf("expected a different nonce to be accepted, got %v", err)
+	}
+}
+
+func TestMemoryRegistry_ConsumeNonceExpires(t *testing.T) {
+	reg := registry.NewInMemoryRegistry()
+	ctx := context.Background()
+
+	if err := reg.ConsumeNonce(ctx, "n1", time.Millisecond); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if err := reg.ConsumeNonce(ctx, "n1", time.Minute); err != nil {
+		t.Fatalf("expected nonce to be forgotten after ttl, got %v", err)
+	}
+}
+
+func TestMemoryRegistry_LatestRevocation(t *testing.T) {
+	reg := registry.NewInMemoryRegistry()
+	ctx := context.Background()
+
+	at, err := reg.LatestRevocation(ctx, "a", "b")
+	if err != nil || !at.IsZero() {
+		t.Fatalf("expected no revocation, got %v %v", at, err)
+	}
+
+	first := time.Now().Add(-time.Minute)
+	second := time.Now()
+	if err := reg.SaveRevocation(ctx, "a", first, time.Minute); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := reg.SaveRevocation(ctx, "b", second, time.Millisecond); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if at, _ := reg.LatestRevocation(ctx, "a", "b"); !at.Equal(second) {
+		t.Fatalf("expected latest revocation %v, got %v", second, at)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if at, _ := reg.LatestRevocation(ctx, "a", "b"); !at.Equal(first) {
+		t.Fatalf("expected expired revocation to be ignored, got %v", at)
+	}
+}
+
+func TestMemoryRegistry_SessionClaims(t *testing.T) {
+	reg := registry.NewInMemoryRegistry()
+	ctx := context.Background()
+
+	if _, err := reg.GetSessionClaims(ctx, "ref"); !errors.Is(err, lti_domain.ErrSessionClaimsNotFound) {
+		t.Fatalf("expected ErrSessionClaimsNotFound, got %v", err)
+	}
+
+	err := reg.SaveSessionClaims(ctx, "ref", lti_domain.SessionClaims{Custom: map[string]any{"k": "v"}}, time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got, err := reg.GetSessionClaims(ctx, "ref"); err != nil || got.Custom["k"] != "v" {
+		t.Fatalf("expected saved claims, got %+v %v", got, err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if _, err := reg.GetSessionClaims(ctx, "ref"); !errors.Is(err, lti_domain.ErrSessionClaimsNotFound) {
+		t.Fatalf("expected expired claims to be gone, got %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package registry_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/registry"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+func setupRedis(t *testing.T) (lti_ports.EphemeralStore, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return registry.NewRedisStore(client), mr
+}
+
+func TestRedisState_RoundTripAndTTL(t *testing.T) {
+	store, mr := setupRedis(t)
+	ctx := context.Background()
+
+	err := store.SaveState(ctx, "state1", lti_domain.State{Nonce: "n1", ClientID: "client1"}, time.Minute)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got, err := store.GetState(ctx, "state1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Nonce != "n1" || got.ClientID != "client1" {
+		t.Errorf("unexpected state %+v", got)
+	}
+
+	mr.FastForward(2 * time.Minute)
+	if _, err := store.GetState(ctx, "state1"); !errors.Is(err, lti_domain.ErrStateNotFound) {
+		t.Fatalf("expected ErrStateNotFound after ttl, got %v", err)
+	}
+}
+
+func TestRedisState_Delete(t *testing.T) {
+	store, _ := setupRedis(t)
+	ctx := context.Background()
+
+	_ = store.SaveState(ctx, "state1", lti_domain.State{Nonce: "n1"}, time.Minute)
+	if err := store.DeleteState(ctx, "state1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := store.DeleteState(ctx, "state1"); !errors.Is(err, lti_domain.ErrStateNotFound) {
+		t.Fatalf("expected ErrStateNotFound on second delete, got %v", err)
+	}
+}
+
+func TestRedisSwapToken_RedeemedOnce(t *testing.T) {
+	store, _ := setupRedis(t)
+	ctx := context.Background()
+
+	swap := lti_domain.SwapToken{To: "/lti/app/", RequestorUA: "ua", Claims: lti_domain.LTIJWT{SessionID: "s1"}}
+	if err := store.SaveSwapToken(ctx, "swap1", swap, time.Minute); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got, err := store.GetAndDeleteSwapToken(ctx, "swap1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.To != "/lti/app/" || got.Claims.SessionID != "s1" {
+		t.Errorf("unexpected swap token %+v", got)
+	}
+
+	if _, err := store.GetAndDeleteSwapToken(ctx, "swap1"); !errors.Is(err, lti_domain.ErrSwapTokenNotFound) {
+		t.Fatalf("expected ErrSwapTokenNotFound on second redeem, got %v", err)
+	}
+}
+
+func TestRedisSwapToken_Expires(t *testing.T) {
+	store, mr := setupRedis(t)
+	ctx := context.Background()
+
+	_ = store.SaveSwapToken(ctx, "swap1", lti_domain.SwapToken{To: "/lti/app/"}, time.Minute)
+	mr.FastForward(2 * time.Minute)
+
+	if _, err := store.GetAndDeleteSwapToken(ctx, "swap1"); !errors.Is(err, lti_domain.ErrSwapTokenNotFound) {
+		t.Fatalf("expected ErrSwapTokenNotFound after ttl, got %v", err)
+	}
+}
+
+func TestRedisSwapToken_ConcurrentRedeem(t *testing.T) {
+	store, _ := setupRedis(t)
+	ctx := context.Background()
+
+	_ = store.SaveSwapToken(ctx, "swap1", lti_domain.SwapToken{To: "/lti/app/"}, time.Minute)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	successes := 0
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := store.GetAndDeleteSwapToken(ctx, "swap1"); err == nil {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if successes != 1 {
+		t.Fatalf("expected exactly one redemption, got %d", successes)
+	}
+}
+
+func TestRedisExchangeToken_ClaimOnce(t *testing.T) {
+	store, _ := setupRedis(t)
+	ctx := context.Background()
+
+	exch := lti_domain.ExchangeToken{
+		Data:           &lti_domain.SwapToken{To: "/lti/app/"},
+		ClaimableUntil: time.Now().UTC().Add(time.Minute),
+	}
+	if err := store.SaveExchangeToken(ctx, "ex1", exch, 10*time.Minute); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	authToken, err := store.ClaimExchangeToken(ctx, "ex1", "challenge1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if authToken == "" {
+		t.Fatalf("expected an auth token")
+	}
+
+	if _, err := store.ClaimExchangeToken(ctx, "ex1", "challenge2"); !errors.Is(err, lti_domain.ErrExchangeTokenAlreadyExchanged) {
+		t.Fatalf("expected ErrExchangeTokenAlreadyExchanged, got %v", err)
+	}
+
+	got, err := store.GetAndDeleteExchangeToken(ctx, "ex1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !got.Exchanged || got.Challenge != "challenge1" || got.AuthToken != authToken {
+		t.Errorf("expected claimed fields to be returned, got %+v", got)
+	}
+	if got.Data == nil || got.Data.To != "/lti/app/" {
+		t.Errorf("expected swap data to be preserved, got %+v", got.Data)
+	}
+
+	if _, err := store.GetAndDeleteExchangeToken(ctx, "ex1"); !errors.Is(err, lti_domain.ErrExchangeTokenNotFound) {
+		t.Fatalf("expected ErrExchangeTokenNotFound on second redeem, got %v", err)
+	}
+}
+
+func TestRedisExchangeToken_ClaimExpired(t *testing.T) {
+	store, _ := setupRedis(t)
+	ctx := context.Background()
+
+	_ = store.SaveExchangeToken(ctx, "ex1", lti_domain.ExchangeToken{
+		ClaimableUntil: time.Now().UTC().Add(-time.Second),
+	}, 10*time.Minute)
+
+	if _, err := store.ClaimExchangeToken(ctx, "ex1", "challenge"); !errors.Is(err, lti_domain.ErrExchangeRedemptionExpired) {
+		t.Fatalf("expected ErrExchangeRedemptionExpired, got %v", err)
+	}
+}
+
+func TestRedisExchangeToken_ClaimMissing(t *testing.T) {
+	store, _ := setupRedis(t)
+
+	if _, err := store.ClaimExchangeToken(context.Background(), "nope", "challenge"); !errors.Is(err, lti_domain.ErrExchangeTokenNotFound) {
+		t.Fatalf("expected ErrExchangeTokenNotFound, got %v", err)
+	}
+}
+
+func TestRedisExchangeToken_ConcurrentClaim(t *testing.T) {
+	store, _ := setupRedis(t)
+	ctx := context.Background()
+
+	_ = store.SaveExchangeToken(ctx, "ex1", lti_domain.ExchangeToken{
+		ClaimableUntil: time.Now().UTC().Add(time.Minute),
+	}, 10*time.Minute)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	successes := 0
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := store.ClaimExchangeToken(ctx, "ex1", "challenge"); err == nil {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if successes != 1 {
+		t.Fatalf("expected exactly one claim, got %d", successes)
+	}
+}
+
+func TestRedisExchangeToken_Expires(t *testing.T) {
+	store, mr := setupRedis(t)
+	ctx := context.Background()
+
+	_ = store.SaveExchangeToken(ctx, "ex1", lti_domain.ExchangeToken{
+		ClaimableUntil: time.Now().UTC().Add(time.Minute),
+	}, 10*time.Minute)
+	mr.FastForward(11 * time.Minute)
+
+	if _, err := store.GetAndDeleteExchangeToken(ctx, "ex1"); !errors.Is(err, lti_domain.ErrExchangeTokenNotFound) {
+		t.Fatalf("expected ErrExchangeTokenNotFound after ttl, got %v", err)
+	}
+}
+
+func TestRedisNonce_ConsumedOnceUntilTTL(t *testing.T) {
+	store, mr := setupRedis(t)
+	ctx := context.Background()
+
+	if err := store.ConsumeNonce(ctx, "n1", time.Minute); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := store.ConsumeNonce(ctx, "n1", time.Minute); !errors.Is(err, lti_domain.ErrNonceReplayed) {
+		t.Fatalf("expected ErrNonceReplayed, got %v", err)
+	}
+
+	mr.FastForward(2 * time.Minute)
+
+	if err := store.ConsumeNonce(ctx, "n1", time.Minute); err != nil {
+		t.Fatalf("expected nonce to be forgotten after ttl, got %v", err)
+	}
+}
+
+func TestRedisNonce_ConcurrentConsume(t *testing.T) {
+	store, _ := setupRedis(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	successes := 0
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := store.ConsumeNonce(ctx, "n1", time.Minute); err == nil {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if successes != 1 {
+		t.Fatalf("expected exactly one consume to succeed, got %d", successes)
+	}
+}
+
+func TestRedisRevocation_LatestUntilTTL(t *testing.T) {
+	store, mr := setupRedis(t)
+	ctx := context.Background()
+
+	if at, err := store.LatestRevocation(ctx, "a", "b"); err != nil || !at.IsZero() {
+		t.Fatalf("expected no revocation, got %v %v", at, err)
+	}
+
+	first := time.Now().Add(-time.Minute)
+	second := time.Now()
+	if err := store.SaveRevocation(ctx, "a", first, 2*time.Minute); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := store.SaveRevocation(ctx, "b", second, time.Minute); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if at, _ := store.LatestRevocation(ctx, "a", "b", "c"); !at.Equal(second) {
+		t.Fatalf("expected latest revocation %v, got %v", second, at)
+	}
+
+	mr.FastForward(90 * time.Second)
+
+	if at, _ := store.LatestRevocation(ctx, "a", "b"); !at.Equal(first) {
+		t.Fatalf("expected expired revocation to be ignored, got %v", at)
+	}
+}
+
+func TestRedisSessionClaims_RoundTripAndTTL(t *testing.T) {
+	store, mr := setupRedis(t)
+	ctx := context.Background()
+
+	err := store.SaveSessionClaims(ctx, "ref", lti_domain.SessionClaims{
+		Custom:          map[string]any{"k": "v"},
+		RoleScopeMentor: []string{"student-1"},
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got, err := store.GetSessionClaims(ctx, "ref")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Custom["k"] != "v" || len(got.RoleScopeMentor) != 1 {
+		t.Errorf("unexpected session claims %+v", got)
+	}
+
+	mr.FastForward(2 * time.Minute)
+	if _, err := store.GetSessionClaims(ctx, "ref"); !errors.Is(err, lti_domain.ErrSessionClaimsNotFound) {
+		t.Fatalf("expected ErrSessionClaimsNotFound after ttl, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package registry_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/registry"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+
+	_ "modernc.org/sqlite"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "registry.db"))
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func setupSQL(t *testing.T, db *sql.DB) lti_ports.Registry {
+	t.Helper()
+	reg, err := registry.NewSQLRegistry(context.Background(), db, registry.WithDialect(registry.DialectSQLite))
+	if err != nil {
+		t.Fatalf("failed to create sql registry: %v", err)
+	}
+	return reg
+}
+
+func sampleDeployment() lti_domain.BaseLTIDeployment {
+	return lti_domain.BaseLTIDeployment{
+		InternalID:    "internal-1",
+		ForTenantID:   "tenantA",
+		Issuer:        "https://lms.example",
+		ClientID:      "client1",
+		JWKSURL:       "https://lms.example/jwks",
+		AuthEndpoint:  "https://lms.example/auth",
+		TokenEndpoint: "https://lms.example/token",
+		DeploymentID:  "dep1",
+	}
+}
+
+func TestSQLRegistry_AddAndGet(t *testing.T) {
+	reg := setupSQL(t, openSQLite(t))
+	ctx := context.Background()
+
+	if err := reg.AddDeployment(ctx, sampleDeployment()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	dep, err := reg.GetDeployment(ctx, "client1", "dep1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dep != lti_domain.Deployment(sampleDeployment()) {
+		t.Errorf("expected %+v, got %+v", sampleDeployment(), dep)
+	}
+}
+
+func TestSQLRegistry_NotFound(t *testing.T) {
+	reg := setupSQL(t, openSQLite(t))
+
+	_, err := reg.GetDeployment(context.Background(), "client1", "missing")
+	if !errors.Is(err, lti_domain.ErrDeploymentNotFound) {
+		t.Fatalf("expected ErrDeploymentNotFound, got %v", err)
+	}
+}
+
+func TestSQLRegistry_AddReplacesExisting(t *testing.T) {
+	reg := setupSQL(t, openSQLite(t))
+	ctx := context.Background()
+
+	_ = reg.AddDeployment(ctx, sampleDeployment())
+	updated := sampleDeployment()
+	updated.JWKSURL = "https://lms.example/jwks-v2"
+	updated.Disabled = true
+	if err := reg.AddDeployment(ctx, &updated); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	dep, err := reg.GetDeployment(ctx, "client1", "dep1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dep.GetLTIJWKSURL() != "https://lms.example/jwks-v2" {
+		t.Errorf("expected replaced jwks url, got %q", dep.GetLTIJWKSURL())
+	}
+	if !lti_domain.IsDeploymentDisabled(dep) {
+		t.Errorf("expected replaced disabled flag")
+	}
+}
+
+func TestSQLRegistry_Update(t *testing.T) {
+	reg := setupSQL(t, openSQLite(t))
+	ctx := context.Background()
+
+	_ = reg.AddDeployment(ctx, sampleDeployment())
+	updated := sampleDeployment()
+	updated.JWKSURL = "https://lms.example/jwks-v2"
+	updated.Disabled = true
+	if err := reg.UpdateDeployment(ctx, &updated); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	dep, err := reg.GetDeployment(ctx, "client1", "dep1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dep.GetLTIJWKSURL() != "https://lms.example/jwks-v2" {
+		t.Errorf("expected updated jwks url, got %q", dep.GetLTIJWKSURL())
+	}
+	if !lti_domain.IsDeploymentDisabled(dep) {
+		t.Errorf("expected deployment to be disabled")
+	}
+
+	missing := sampleDeployment()
+	missing.DeploymentID = "missing"
+	if err := reg.UpdateDeployment(ctx, missing); !errors.Is(err, lti_domain.ErrDeploymentNotFound) {
+		t.Fatalf("expected ErrDeploymentNotFound, got %v", err)
+	}
+}
+
+func TestSQLRegistry_Remove(t *testing.T) {
+	reg := setupSQL(t, openSQLite(t))
+	ctx := context.Background()
+
+	_ = reg.AddDeployment(ctx, sampleDeployment())
+	if err := reg.RemoveDeployment(ctx, "client1", "dep1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := reg.GetDeployment(ctx, "client1", "dep1"); !errors.Is(err, lti_domain.ErrDeploymentNotFound) {
+		t.Fatalf("expected ErrDeploymentNotFound after remove, got %v", err)
+	}
+	if err := reg.RemoveDeployment(ctx, "client1", "dep1"); !errors.Is(err, lti_domain.ErrDeploymentNotFound) {
+		t.Fatalf("expected ErrDeploymentNotFound on second remove, got %v", err)
+	}
+}
+
+func TestSQLRegistry_ListFilters(t *testing.T) {
+	reg := setupSQL(t, openSQLite(t))
+	ctx := context.Background()
+
+	a := sampleDeployment()
+	b := sampleDeployment()
+	b.DeploymentID, b.ForTenantID = "dep2", "tenantB"
+	c := sampleDeployment()
+	c.DeploymentID, c.Issuer = "dep3", "https://other.example"
+	for _, dep := range []lti_domain.BaseLTIDeployment{a, b, c} {
+		if err := reg.AddDeployment(ctx, dep); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	all, _ := reg.ListDeployments(ctx, lti_domain.DeploymentFilter{})
+	if len(all) != 3 {
+		t.Fatalf("expected 3 deployments, got %d", len(all))
+	}
+
+	byTenant, _ := reg.ListDeployments(ctx, lti_domain.DeploymentFilter{TenantID: "tenantA"})
+	if len(byTenant) != 2 {
+		t.Errorf("expected 2 deployments for tenantA, got %d", len(byTenant))
+	}
+
+	byIssuer, _ := reg.ListDeployments(ctx, lti_domain.DeploymentFilter{TenantID: "tenantA", Issuer: "https://other.example"})
+	if len(byIssuer) != 1 || byIssuer[0].GetLTIDeploymentID() != "dep3" {
+		t.Errorf("expected only dep3, got %v", byIssuer)
+	}
+}
+
+func TestSQLRegistry_PersistsAcrossInstances(t *testing.T) {
+	db := openSQLite(t)
+	ctx := context.Background()
+
+	if err := setupSQL(t, db).AddDeployment(ctx, sampleDeployment()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// A second instance re-runs migrations against an up-to-date schema.
+	dep, err := setupSQL(t, db).GetDeployment(ctx, "client1", "dep1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dep.GetTenantID() != "tenantA" {
+		t.Errorf("expected tenant %q, got %v", "tenantA", dep.GetTenantID())
+	}
+}
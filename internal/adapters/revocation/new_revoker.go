@@ -0,0 +1,55 @@
+package revocation
+
+import (
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/clock"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_logger"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+func NewRevoker(opts ...lti_ports.SessionRevokerOption) lti_ports.SessionRevoker {
+	r := &Revoker{
+		clock:  clock.NewSystemClock(),
+		logger: lti_logger.NewNoopLogger(),
+		policy: lti_domain.DefaultPolicy(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.store == nil {
+		panic("an ephemeral store is required for a session revoker. Call with WithStore")
+	}
+
+	return r
+}
+
+func WithStore(store lti_ports.EphemeralStore) lti_ports.SessionRevokerOption {
+	return func(s lti_ports.SessionRevoker) {
+		cast := s.(*Revoker)
+		cast.store = store
+	}
+}
+
+// WithPolicy sets the session policy the launcher issues sessions with. Only
+// MaxSessionLifetime is used, to know how long revocations must be kept.
+func WithPolicy(policy lti_domain.Policy) lti_ports.SessionRevokerOption {
+	return func(s lti_ports.SessionRevoker) {
+		cast := s.(*Revoker)
+		cast.policy = policy.WithDefaults()
+	}
+}
+
+func WithClock(clock lti_ports.Clock) lti_ports.SessionRevokerOption {
+	return func(s lti_ports.SessionRevoker) {
+		cast := s.(*Revoker)
+		cast.clock = clock
+	}
+}
+
+func WithLogger(logger lti_ports.Logger) lti_ports.SessionRevokerOption {
+	return func(s lti_ports.SessionRevoker) {
+		cast := s.(*Revoker)
+		cast.logger = logger
+	}
+}
@@ -0,0 +1,111 @@
+package revocation
+
+import (
+	"context"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.SessionRevoker = (*Revoker)(nil)
+
+// Revoker keeps revocations in the EphemeralStore. Every revocation is stored
+// with the time it was made; a session is revoked when it started at or before
+// the latest revocation recorded for its session ID, token ID, user or tenant.
+type Revoker struct {
+	store  lti_ports.EphemeralStore
+	policy lti_domain.Policy
+	clock  lti_ports.Clock
+	logger lti_ports.Logger
+}
+
+func sessionKey(sessionID string) string { return "session:" + sessionID }
+func tokenKey(jti string) string         { return "token:" + jti }
+func userKey(tenantID, userID string) string {
+	return "user:" + tenantID + ":" + userID
+}
+func tenantKey(tenantID string) string { return "tenant:" + tenantID }
+
+func sessionStart(session *lti_domain.LTIJWT) time.Time {
+	switch {
+	case session.SessionStart != nil:
+		return session.SessionStart.Time
+	case session.IssuedAt != nil:
+		return session.IssuedAt.Time
+	}
+	return time.Time{}
+}
+
+// RevokeSession revokes the session and the token it was presented with. The
+// session entry is kept until the session's maximum lifetime runs out, since
+// refreshed tokens outlive the one being revoked.
+func (r *Revoker) RevokeSession(ctx context.Context, session *lti_domain.LTIJWT) error {
+	now := r.clock.Now()
+
+	if session.ID != "" && session.ExpiresAt != nil {
+		if ttl := session.ExpiresAt.Sub(now); ttl > 0 {
+			if err := r.store.SaveRevocation(ctx, tokenKey(session.ID), now, ttl); err != nil {
+				return err
+			}
+		}
+	}
+
+	if session.SessionID != "" {
+		ttl := sessionStart(session).Add(r.policy.MaxSessionLifetime).Sub(now)
+		if ttl > 0 {
+			if err := r.store.SaveRevocation(ctx, sessionKey(session.SessionID), now, ttl); err != nil {
+				return err
+			}
+		}
+	}
+
+	r.logger.Info("session revoked", "session_id", session.SessionID, "user_id", session.UserInfo.UserID, "tenant_id", session.TenantID)
+	return nil
+}
+
+// RevokeUser revokes every session the user started in the tenant so far.
+func (r *Revoker) RevokeUser(ctx context.Context, tenantID, userID string) error {
+	if err := r.store.SaveRevocation(ctx, userKey(tenantID, userID), r.clock.Now(), r.policy.MaxSessionLifetime); err != nil {
+		return err
+	}
+	r.logger.Info("user sessions revoked", "user_id", userID, "tenant_id", tenantID)
+	return nil
+}
+
+// RevokeTenant revokes every session started in the tenant so far.
+func (r *Revoker) RevokeTenant(ctx context.Context, tenantID string) error {
+	if err := r.store.SaveRevocation(ctx, tenantKey(tenantID), r.clock.Now(), r.policy.MaxSessionLifetime); err != nil {
+		return err
+	}
+	r.logger.Info("tenant sessions revoked", "tenant_id", tenantID)
+	return nil
+}
+
+func (r *Revoker) CheckSession(ctx context.Context, session *lti_domain.LTIJWT) error {
+	keys := []string{
+		userKey(session.TenantID, session.UserInfo.UserID),
+		tenantKey(session.TenantID),
+	}
+	if session.SessionID != "" {
+		keys = append(keys, sessionKey(session.SessionID))
+	}
+	if session.ID != "" {
+		keys = append(keys, tokenKey(session.ID))
+	}
+
+	revokedAt, err := r.store.LatestRevocation(ctx, keys...)
+	if err != nil {
+		return err
+	}
+	if revokedAt.IsZero() {
+		return nil
+	}
+
+	// Session times only carry whole seconds, so a session started in the
+	// same second as the revocation counts as revoked.
+	if !sessionStart(session).After(revokedAt.Truncate(time.Second)) {
+		return lti_domain.ErrSessionRevoked
+	}
+	return nil
+}
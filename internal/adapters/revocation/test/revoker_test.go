@@ -0,0 +1,113 @@
+package revocation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/registry"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/revocation"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+func setupRevoker(now time.Time) (lti_ports.SessionRevoker, *lti_testadapters.FakeClock) {
+	clock := lti_testadapters.NewFakeClock(now)
+	return revocation.NewRevoker(
+		revocation.WithStore(registry.NewInMemoryRegistry()),
+		revocation.WithClock(clock),
+	), clock
+}
+
+func newSession(sessionID, jti, userID string, start time.Time) *lti_domain.LTIJWT {
+	return &lti_domain.LTIJWT{
+		SessionID:    sessionID,
+		SessionStart: jwt.NewNumericDate(start),
+		TenantID:     "tenant-1",
+		UserInfo:     lti_domain.LTIJWT_UserInfo{UserID: userID},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(start),
+			ExpiresAt: jwt.NewNumericDate(start.Add(time.Hour)),
+		},
+	}
+}
+
+func mustBeRevoked(t *testing.T, r lti_ports.SessionRevoker, s *lti_domain.LTIJWT, want bool) {
+	t.Helper()
+	err := r.CheckSession(context.Background(), s)
+	if want && !errors.Is(err, lti_domain.ErrSessionRevoked) {
+		t.Fatalf("expected session %s to be revoked, got %v", s.SessionID, err)
+	}
+	if !want && err != nil {
+		t.Fatalf("expected session %s to be valid, got %v", s.SessionID, err)
+	}
+}
+
+func TestRevoker_RevokeSessionCoversRefreshedTokens(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	r, _ := setupRevoker(time.Now())
+	ctx := context.Background()
+
+	current := newSession("s1", "jti-1", "u1", start)
+	refreshed := newSession("s1", "jti-2", "u1", start)
+	other := newSession("s2", "jti-3", "u1", start)
+
+	if err := r.RevokeSession(ctx, current); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	mustBeRevoked(t, r, current, true)
+	mustBeRevoked(t, r, refreshed, true)
+	mustBeRevoked(t, r, other, false)
+}
+
+func TestRevoker_RevokeUser(t *testing.T) {
+	now := time.Now()
+	r, clock := setupRevoker(now)
+	ctx := context.Background()
+
+	before := newSession("s1", "jti-1", "u1", now.Add(-time.Hour))
+	otherUser := newSession("s2", "jti-2", "u2", now.Add(-time.Hour))
+
+	if err := r.RevokeUser(ctx, "tenant-1", "u1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	mustBeRevoked(t, r, before, true)
+	mustBeRevoked(t, r, otherUser, false)
+
+	clock.Advance(2 * time.Second)
+	after := newSession("s3", "jti-3", "u1", clock.Now())
+	mustBeRevoked(t, r, after, false)
+}
+
+func TestRevoker_RevokeTenant(t *testing.T) {
+	now := time.Now()
+	r, _ := setupRevoker(now)
+
+	a := newSession("s1", "jti-1", "u1", now.Add(-time.Hour))
+	b := newSession("s2", "jti-2", "u2", now.Add(-time.Minute))
+	elsewhere := newSession("s3", "jti-3", "u1", now.Add(-time.Minute))
+	elsewhere.TenantID = "tenant-2"
+
+	if err := r.RevokeTenant(context.Background(), "tenant-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	mustBeRevoked(t, r, a, true)
+	mustBeRevoked(t, r, b, true)
+	mustBeRevoked(t, r, elsewhere, false)
+}
+
+func TestRevoker_RequiresStore(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected NewRevoker to panic without a store")
+		}
+	}()
+	revocation.NewRevoker()
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/session"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+func writeLogoutError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"err": msg})
+}
+
+// handleLogout ends the current session: it is revoked when the server has a
+// revoker, and its cookies are cleared either way.
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeLogoutError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	claims, ok := lti_domain.LTIFromContext(r.Context())
+	if !ok {
+		writeLogoutError(w, http.StatusUnauthorized, "missing token")
+		return
+	}
+
+	if s.revoker != nil {
+		if err := s.revoker.RevokeSession(r.Context(), claims); err != nil {
+			writeLogoutError(w, http.StatusInternalServerError, "failed to revoke session")
+			return
+		}
+	}
+
+	session.ClearCookies(w, r, lti_domain.RoutesFromContext(r.Context()).App()+"/")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusNoContent)
+}
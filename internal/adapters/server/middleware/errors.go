@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+// jsonErrorsKey marks requests verified by VerifyLTIBearer, so the
+// middleware after it answers with JSON instead of redirecting.
+type jsonErrorsKey struct{}
+
+func wantsJSONErrors(r *http.Request) bool {
+	v, _ := r.Context().Value(jsonErrorsKey{}).(bool)
+	return v
+}
+
+func writeJSONError(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	trace, ok := r.Context().Value("trace_id").(string)
+	if !ok {
+		trace = rand.Text()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"err":   msg,
+		"trace": trace,
+	})
+}
+
+// authError rejects a verified session: API routes get a JSON status, pages
+// are sent to the auth error page.
+func authError(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	if wantsJSONErrors(r) {
+		writeJSONError(w, r, status, msg)
+		return
+	}
+	http.Redirect(w, r, lti_domain.RoutesFromContext(r.Context()).ErrorURL(msg), http.StatusTemporaryRedirect)
+}
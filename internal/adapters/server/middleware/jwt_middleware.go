@@ -4,12 +4,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"net/url"
 	"slices"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/vizdos-enterprises/go-lti/internal/adapters/deeplinking"
-	"github.com/vizdos-enterprises/go-lti/lti/lti_deeplink"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
 )
@@ -45,61 +43,123 @@ func parseAndValidate[T jwt.Claims](verifier lti_ports.Verifier, expectedAudienc
 
 func VerifyLTI(verifier lti_ports.Verifier, expectedAudience []string, allowImpostering bool, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cookie, err := r.Cookie(lti_domain.ContextKey_Session)
+		cookie, err := lti_domain.CookiePolicyFromContext(r.Context()).Read(r, lti_domain.ContextKey_Session)
 		if err != nil {
-			params := url.Values{}
-			params.Add("err", "missing token")
-			http.Redirect(w, r, "/lti/auth/error?"+params.Encode(), http.StatusTemporaryRedirect)
+			http.Redirect(w, r, lti_domain.RoutesFromContext(r.Context()).ErrorURL("missing token"), http.StatusTemporaryRedirect)
 			return
 		}
 
 		claims, err := parseAndValidate[lti_domain.LTIJWT](verifier, expectedAudience, cookie.Value)
+		if err == nil {
+			err = checkRevoked(r, claims)
+		}
+		if err == nil {
+			err = loadSessionClaims(r, claims)
+		}
 		if err != nil {
-			params := url.Values{}
-			params.Add("err", err.Error())
-			http.Redirect(w, r, "/lti/auth/error?"+params.Encode(), http.StatusTemporaryRedirect)
+			http.Redirect(w, r, lti_domain.RoutesFromContext(r.Context()).ErrorURL(err.Error()), http.StatusTemporaryRedirect)
 			return
 		}
 
-		ctx := r.Context()
-		if claims.LaunchType == lti_domain.LTIService_DeepLink {
-			deepLinkCookie, err := r.Cookie(deeplinking.ContextKey_DeepLink)
-			if err != nil {
-				http.Error(w, "missing token", http.StatusUnauthorized)
-				return
-			}
+		if !allowImpostering && claims.Impostering {
+			http.Error(w, "impostering not allowed", http.StatusForbidden)
+			return
+		}
 
-			deepLinkContext, err := parseAndValidate[lti_domain.DeepLinkContext](verifier, []string{}, deepLinkCookie.Value)
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusUnauthorized)
-				return
-			}
+		serveSession(w, r, next, claims, cookie.Value)
+	})
+}
+
+// VerifyLTIBearer is VerifyLTI for API routes. The session is read from an
+// "Authorization: Bearer" header, falling back to the session cookie, and
+// failures are JSON errors instead of redirects to the auth error page.
+func VerifyLTIBearer(verifier lti_ports.Verifier, expectedAudience []string, allowImpostering bool, next http.Handler) http.Handler {
+	return verifyBearer(lti_domain.ContextKey_Session, verifier, expectedAudience, allowImpostering, next)
+}
+
+// VerifySessionRefresh guards the refresh and logout endpoints. It behaves
+// like VerifyLTIBearer but reads the companion refresh cookie, the only
+// session cookie scoped to the auth routes.
+//
+// The refresh cookie is SameSite=None so it works inside the platform's
+// iframe, which means another site's form could POST with it. Cookie requests
+// that change state must therefore carry an X-Requested-With header, which a
+// cross-site page can't set without passing CORS.
+func VerifySessionRefresh(verifier lti_ports.Verifier, expectedAudience []string, next http.Handler) http.Handler {
+	verify := verifyBearer(lti_domain.ContextKey_SessionRefresh, verifier, expectedAudience, true, next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := bearerToken(r); !ok && !safeMethod(r.Method) && r.Header.Get(requestedWithHeader) == "" {
+			writeJSONError(w, r, http.StatusForbidden, "missing "+requestedWithHeader+" header")
+			return
+		}
+		verify.ServeHTTP(w, r)
+	})
+}
 
-			if deepLinkContext.AttachedKID != claims.ID {
-				http.Error(w, "invalid deep link, mismatch kid", http.StatusUnauthorized)
+const requestedWithHeader = "X-Requested-With"
+
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func verifyBearer(cookieBase string, verifier lti_ports.Verifier, expectedAudience []string, allowImpostering bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(context.WithValue(r.Context(), jsonErrorsKey{}, true))
+
+		raw, ok := bearerToken(r)
+		if !ok {
+			cookie, err := lti_domain.CookiePolicyFromContext(r.Context()).Read(r, cookieBase)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeJSONError(w, r, http.StatusUnauthorized, "missing token")
 				return
 			}
+			raw = cookie.Value
+		}
 
-			ctx = lti_deeplink.ContextWithDeepLink(ctx, deepLinkContext)
-			ctx = context.WithValue(ctx, "rawDeepLink", deepLinkCookie)
+		claims, err := parseAndValidate[lti_domain.LTIJWT](verifier, expectedAudience, raw)
+		if err == nil {
+			err = checkRevoked(r, claims)
+		}
+		if err == nil {
+			err = loadSessionClaims(r, claims)
+		}
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeJSONError(w, r, http.StatusUnauthorized, err.Error())
+			return
 		}
 
 		if !allowImpostering && claims.Impostering {
-			http.Error(w, "impostering not allowed", http.StatusForbidden)
+			writeJSONError(w, r, http.StatusForbidden, "impostering not allowed")
 			return
 		}
 
-		sessionID := claims.SessionID
-		if phSessionID := r.Header.Get("X-POSTHOG-SESSION-ID"); phSessionID != "" {
-			// If present, utilize the PostHog one more..
-			sessionID = phSessionID
-		}
+		serveSession(w, r, next, claims, raw)
+	})
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
 
-		// Attach to context
-		ctx = lti_domain.ContextWithLTI(ctx, claims)
-		ctx = context.WithValue(ctx, "rawJWT", cookie.Value)
-		ctx = context.WithValue(ctx, lti_domain.ContextKey_SessionID, sessionID)
+// serveSession attaches a verified session to the request context.
+func serveSession(w http.ResponseWriter, r *http.Request, next http.Handler, claims *lti_domain.LTIJWT, raw string) {
+	sessionID := claims.SessionID
+	if phSessionID := r.Header.Get("X-POSTHOG-SESSION-ID"); phSessionID != "" {
+		// If present, utilize the PostHog one more..
+		sessionID = phSessionID
+	}
 
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+	// Attach to context
+	ctx := lti_domain.ContextWithLTI(r.Context(), claims)
+	ctx = context.WithValue(ctx, lti_domain.ContextKey_RawSession, raw)
+	ctx = context.WithValue(ctx, lti_domain.ContextKey_SessionID, sessionID)
+
+	next.ServeHTTP(w, r.WithContext(ctx))
 }
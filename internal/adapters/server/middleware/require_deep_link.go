@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/deeplinking"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_deeplink"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// RequireDeepLink only lets deep linking sessions through. The deep link
+// cookie must verify and be bound to the current session; it is then attached
+// to the request context for lti_deeplink.ReplyToDeeplink.
+func RequireDeepLink(verifier lti_ports.Verifier) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session, ok := lti_domain.LTIFromContext(r.Context())
+			if !ok {
+				http.Error(w, "missing LTI session", http.StatusUnauthorized)
+				return
+			}
+
+			if session.LaunchType != lti_domain.LTIService_DeepLink {
+				deepLinkError(w, r, "this page is only available when adding content from your LMS")
+				return
+			}
+
+			deepLinkCookie, err := lti_domain.CookiePolicyFromContext(r.Context()).Read(r, deeplinking.ContextKey_DeepLink)
+			if err != nil {
+				deepLinkError(w, r, "missing deep link context, relaunch from your LMS")
+				return
+			}
+
+			deepLinkContext, err := parseAndValidate[lti_domain.DeepLinkContext](verifier, []string{}, deepLinkCookie.Value)
+			if err != nil {
+				deepLinkError(w, r, "invalid deep link context, relaunch from your LMS")
+				return
+			}
+
+			// Bound to the SessionID rather than the token's jti, which
+			// changes on every refresh.
+			if deepLinkContext.AttachedKID == "" || deepLinkContext.AttachedKID != session.SessionID {
+				deepLinkError(w, r, "deep link context does not belong to this session")
+				return
+			}
+
+			ctx := lti_deeplink.ContextWithDeepLink(r.Context(), deepLinkContext)
+			ctx = context.WithValue(ctx, "rawDeepLink", deepLinkCookie)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func deepLinkError(w http.ResponseWriter, r *http.Request, msg string) {
+	authError(w, r, http.StatusForbidden, msg)
+}
@@ -28,7 +28,28 @@ func RequireRole(requiredRoles ...lti_domain.Role) func(next http.Handler) http.
 				}
 			}
 
-			http.Redirect(w, r, "/lti/auth/error?err=role", http.StatusTemporaryRedirect)
+			authError(w, r, http.StatusForbidden, "role")
+		})
+	}
+}
+
+// RequireLaunchType only lets through sessions started by one of types. No
+// types allows every session.
+func RequireLaunchType(types ...lti_domain.LTIService) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session, ok := lti_domain.LTIFromContext(r.Context())
+			if !ok {
+				http.Error(w, "missing LTI session", http.StatusUnauthorized)
+				return
+			}
+
+			if len(types) > 0 && !slices.Contains(types, session.LaunchType) {
+				authError(w, r, http.StatusForbidden, "launch type")
+				return
+			}
+
+			next.ServeHTTP(w, r)
 		})
 	}
 }
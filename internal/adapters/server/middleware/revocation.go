@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+type revokerKey struct{}
+
+// ContextWithRevoker makes revoker available to the session verifiers.
+func ContextWithRevoker(ctx context.Context, revoker lti_ports.SessionRevoker) context.Context {
+	return context.WithValue(ctx, revokerKey{}, revoker)
+}
+
+// checkRevoked rejects sessions revoked through the server's revoker. Without
+// one, every session passes.
+func checkRevoked(r *http.Request, claims *lti_domain.LTIJWT) error {
+	revoker, ok := r.Context().Value(revokerKey{}).(lti_ports.SessionRevoker)
+	if !ok {
+		return nil
+	}
+
+	err := revoker.CheckSession(r.Context(), claims)
+	if err != nil && !errors.Is(err, lti_domain.ErrSessionRevoked) {
+		return fmt.Errorf("could not check session")
+	}
+	return err
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+type claimStoreKey struct{}
+
+// ContextWithSessionClaimStore makes store available to the session verifiers
+// for sessions whose claims are kept server-side.
+func ContextWithSessionClaimStore(ctx context.Context, store lti_ports.EphemeralStore) context.Context {
+	return context.WithValue(ctx, claimStoreKey{}, store)
+}
+
+// loadSessionClaims merges the claims kept server-side for claims back into
+// it. Sessions that keep every claim in their token pass unchanged.
+func loadSessionClaims(r *http.Request, claims *lti_domain.LTIJWT) error {
+	if claims.ClaimsRef == "" {
+		return nil
+	}
+
+	store, ok := r.Context().Value(claimStoreKey{}).(lti_ports.EphemeralStore)
+	if !ok {
+		return fmt.Errorf("could not load session")
+	}
+
+	stored, err := store.GetSessionClaims(r.Context(), claims.ClaimsRef)
+	if errors.Is(err, lti_domain.ErrSessionClaimsNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("could not load session")
+	}
+
+	claims.MergeSessionClaims(*stored)
+	return nil
+}
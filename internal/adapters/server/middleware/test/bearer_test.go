@@ -0,0 +1,207 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/server/middleware"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+func callBearer(t *testing.T, mw http.Handler, authorization string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	w := httptest.NewRecorder()
+	mw.ServeHTTP(w, req)
+	return w
+}
+
+func jsonErr(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected JSON error, got content type %q", ct)
+	}
+	var body map[string]string
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode error body: %v", err)
+	}
+	if body["trace"] == "" {
+		t.Fatalf("expected a trace in the error body")
+	}
+	return body["err"]
+}
+
+func TestVerifyLTIBearer_AcceptsBearerHeader(t *testing.T) {
+	v := &fakeVerifier{shouldBeValid: true, audience: []string{"tool.example"}}
+	var raw string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := lti_domain.LTIFromContext(r.Context()); !ok {
+			t.Fatal("expected LTI claims in context")
+		}
+		raw, _ = lti_domain.RawSessionFromContext(r.Context())
+	})
+
+	w := callBearer(t, middleware.VerifyLTIBearer(v, []string{"tool.example"}, true, next), "Bearer header.jwt")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if raw != "header.jwt" {
+		t.Fatalf("expected raw token from header, got %q", raw)
+	}
+}
+
+func TestVerifyLTIBearer_FallsBackToCookie(t *testing.T) {
+	v := &fakeVerifier{shouldBeValid: true, audience: []string{"tool.example"}}
+	var raw string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ = lti_domain.RawSessionFromContext(r.Context())
+	})
+
+	cookie := &http.Cookie{Name: lti_domain.ContextKey_Session, Value: "cookie.jwt"}
+	w := callBearer(t, middleware.VerifyLTIBearer(v, []string{"tool.example"}, true, next), "Basic abc", cookie)
+
+	if w.Code != http.StatusOK || raw != "cookie.jwt" {
+		t.Fatalf("expected cookie session, got %d %q", w.Code, raw)
+	}
+}
+
+func TestVerifyLTIBearer_JSONErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		verifier      *fakeVerifier
+		authorization string
+		impostering   bool
+		wantStatus    int
+		wantErr       string
+	}{
+		{"missing token", &fakeVerifier{}, "", true, http.StatusUnauthorized, "missing token"},
+		{"empty bearer", &fakeVerifier{}, "Bearer ", true, http.StatusUnauthorized, "missing token"},
+		{"invalid token", &fakeVerifier{shouldError: true}, "Bearer bad.jwt", true, http.StatusUnauthorized, "invalid token"},
+		{"wrong audience", &fakeVerifier{shouldBeValid: true, audience: []string{"other"}}, "Bearer good.jwt", true, http.StatusUnauthorized, "could not verify audience"},
+		{"impostering", &fakeVerifier{shouldBeValid: true, audience: []string{"tool.example"}, impostering: true}, "Bearer good.jwt", false, http.StatusForbidden, "impostering not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+			w := callBearer(t, middleware.VerifyLTIBearer(tt.verifier, []string{"tool.example"}, tt.impostering, next), tt.authorization)
+
+			if called {
+				t.Fatal("expected handler not to be invoked")
+			}
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Fatal("expected WWW-Authenticate header")
+			}
+			if got := jsonErr(t, w); got != tt.wantErr {
+				t.Fatalf("expected err=%q, got %q", tt.wantErr, got)
+			}
+		})
+	}
+}
+
+func TestVerifyLTIBearer_RoleFailureIsJSON(t *testing.T) {
+	v := &fakeVerifier{shouldBeValid: true, audience: []string{"tool.example"}, roles: []lti_domain.Role{lti_domain.MEMBERSHIP_LEARNER}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { t.Fatal("expected handler not to be invoked") })
+
+	roleChecked := middleware.RequireRole(lti_domain.MEMBERSHIP_INSTRUCTOR)(next)
+	w := callBearer(t, middleware.VerifyLTIBearer(v, []string{"tool.example"}, true, roleChecked), "Bearer good.jwt")
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+	if got := jsonErr(t, w); got != "role" {
+		t.Fatalf("expected err=%q, got %q", "role", got)
+	}
+}
+
+func TestVerifySessionRefresh_ReadsRefreshCookie(t *testing.T) {
+	v := &fakeVerifier{shouldBeValid: true, audience: []string{"tool.example"}}
+	var raw string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ = lti_domain.RawSessionFromContext(r.Context())
+	})
+	mw := middleware.VerifySessionRefresh(v, []string{"tool.example"}, next)
+
+	session := &http.Cookie{Name: lti_domain.ContextKey_Session, Value: "session.jwt"}
+	if w := callBearer(t, mw, "", session); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the app session cookie to be ignored, got %d", w.Code)
+	}
+
+	refresh := &http.Cookie{Name: lti_domain.ContextKey_SessionRefresh, Value: "refresh.jwt"}
+	w := callBearer(t, mw, "", refresh)
+	if w.Code != http.StatusOK || raw != "refresh.jwt" {
+		t.Fatalf("expected refresh cookie session, got %d %q", w.Code, raw)
+	}
+}
+
+func TestVerifySessionRefresh_CookiePostNeedsRequestedWith(t *testing.T) {
+	v := &fakeVerifier{shouldBeValid: true, audience: []string{"tool.example"}}
+	mw := middleware.VerifySessionRefresh(v, []string{"tool.example"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	post := func(header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/lti/auth/refresh", nil)
+		req.AddCookie(&http.Cookie{Name: lti_domain.ContextKey_SessionRefresh, Value: "refresh.jwt"})
+		if header != "" {
+			req.Header.Set("X-Requested-With", header)
+		}
+		w := httptest.NewRecorder()
+		mw.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("")
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without the header, got %d", w.Code)
+	}
+	if msg := jsonErr(t, w); msg != "missing X-Requested-With header" {
+		t.Errorf("unexpected error %q", msg)
+	}
+	if w := post("XMLHttpRequest"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 with the header, got %d", w.Code)
+	}
+}
+
+type revokeAll struct{}
+
+func (revokeAll) RevokeSession(context.Context, *lti_domain.LTIJWT) error { return nil }
+func (revokeAll) RevokeUser(context.Context, string, string) error        { return nil }
+func (revokeAll) RevokeTenant(context.Context, string) error              { return nil }
+func (revokeAll) CheckSession(context.Context, *lti_domain.LTIJWT) error {
+	return lti_domain.ErrSessionRevoked
+}
+
+func TestVerifyLTIBearer_RejectsRevokedSession(t *testing.T) {
+	v := &fakeVerifier{shouldBeValid: true, audience: []string{"tool.example"}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected revoked session to be rejected")
+	})
+	mw := middleware.VerifyLTIBearer(v, []string{"tool.example"}, true, next)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+	req = req.WithContext(middleware.ContextWithRevoker(req.Context(), revokeAll{}))
+	req.Header.Set("Authorization", "Bearer header.jwt")
+	w := httptest.NewRecorder()
+	mw.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+	if msg := jsonErr(t, w); msg != "session revoked" {
+		t.Fatalf("expected session revoked, got %q", msg)
+	}
+}
@@ -22,6 +22,9 @@ type fakeVerifier struct {
 	shouldError   bool
 	shouldBeValid bool
 	audience      []string
+	impostering   bool
+	roles         []lti_domain.Role
+	claimsRef     string
 }
 
 func (fakeVerifier) Sign(claims jwt.Claims, ttl time.Duration) (string, error) {
@@ -44,6 +47,9 @@ func (f *fakeVerifier) Verify(tokenString string, claims jwt.Claims) (*jwt.Token
 	// populate some claims
 	if lti, ok := claims.(*lti_domain.LTIJWT); ok {
 		lti.Audience = f.audience
+		lti.Impostering = f.impostering
+		lti.Roles = f.roles
+		lti.ClaimsRef = f.claimsRef
 	}
 
 	tok := &jwt.Token{
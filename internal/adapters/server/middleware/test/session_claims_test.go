@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/server/middleware"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+func callWithClaimStore(t *testing.T, v *fakeVerifier, store *lti_testadapters.FakeRegistry, next http.Handler) *httptest.ResponseRecorder {
+	t.Helper()
+	mw := middleware.VerifyLTIBearer(v, []string{"tool.example"}, true, next)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+	if store != nil {
+		req = req.WithContext(middleware.ContextWithSessionClaimStore(req.Context(), store))
+	}
+	req.Header.Set("Authorization", "Bearer header.jwt")
+	w := httptest.NewRecorder()
+	mw.ServeHTTP(w, req)
+	return w
+}
+
+func TestVerifyLTIBearer_LoadsStoredClaims(t *testing.T) {
+	store := &lti_testadapters.FakeRegistry{}
+	_ = store.SaveSessionClaims(context.Background(), "ref-1", lti_domain.SessionClaims{
+		Custom:          map[string]any{"course_theme": "dark"},
+		RoleScopeMentor: []string{"student-1"},
+	}, time.Hour)
+
+	var got *lti_domain.LTIJWT
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = lti_domain.LTIFromContext(r.Context())
+	})
+	v := &fakeVerifier{shouldBeValid: true, audience: []string{"tool.example"}, claimsRef: "ref-1"}
+
+	w := callWithClaimStore(t, v, store, next)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if got == nil || got.Custom["course_theme"] != "dark" || len(got.RoleScopeMentor) != 1 {
+		t.Fatalf("expected stored claims merged into the session, got %+v", got)
+	}
+}
+
+func TestVerifyLTIBearer_StoredClaimsMissing(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected session without its stored claims to be rejected")
+	})
+	v := &fakeVerifier{shouldBeValid: true, audience: []string{"tool.example"}, claimsRef: "gone"}
+
+	w := callWithClaimStore(t, v, &lti_testadapters.FakeRegistry{}, next)
+	if w.Code != http.StatusUnauthorized || jsonErr(t, w) != "session claims not found" {
+		t.Fatalf("expected 401 session claims not found, got %d", w.Code)
+	}
+}
+
+func TestVerifyLTIBearer_StoredClaimsWithoutStore(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected session to be rejected without a claim store")
+	})
+	v := &fakeVerifier{shouldBeValid: true, audience: []string{"tool.example"}, claimsRef: "ref-1"}
+
+	w := callWithClaimStore(t, v, nil, next)
+	if w.Code != http.StatusUnauthorized || jsonErr(t, w) != "could not load session" {
+		t.Fatalf("expected 401 could not load session, got %d", w.Code)
+	}
+}
@@ -1,12 +1,16 @@
 package server
 
-import "github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+import (
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
 
 type ServerOption func(*Server)
 
 func NewServer(opts ...ServerOption) *Server {
 	s := &Server{
 		launcher: nil, // must be set via option
+		routes:   lti_domain.DefaultRoutes(),
 	}
 
 	for _, opt := range opts {
@@ -42,3 +46,42 @@ func WithImpostering(im lti_ports.Impostering) ServerOption {
 		s.impostering = im
 	}
 }
+
+func WithDynamicRegistration(reg lti_ports.DynamicRegistration) ServerOption {
+	return func(s *Server) {
+		s.registration = reg
+	}
+}
+
+// WithRoutes mounts the LTI endpoints under routes instead of the default /lti
+// layout. The configuration is passed to every adapter through the request
+// context.
+func WithRoutes(routes lti_domain.Routes) ServerOption {
+	return func(s *Server) {
+		s.routes = routes.Normalize()
+	}
+}
+
+// WithCookiePolicy sets the name and attributes of every cookie set or read
+// while serving requests. Defaults to the zero lti_domain.CookiePolicy.
+func WithCookiePolicy(policy lti_domain.CookiePolicy) ServerOption {
+	return func(s *Server) {
+		s.cookies = policy
+	}
+}
+
+// WithSessionRevoker checks every session against revoker and makes the
+// logout route revoke the session it ends.
+func WithSessionRevoker(revoker lti_ports.SessionRevoker) ServerOption {
+	return func(s *Server) {
+		s.revoker = revoker
+	}
+}
+
+// WithSessionClaimStore loads the claims the launcher kept in store for
+// sessions larger than Policy.MaxSessionClaimsSize.
+func WithSessionClaimStore(store lti_ports.EphemeralStore) ServerOption {
+	return func(s *Server) {
+		s.claimStore = store
+	}
+}
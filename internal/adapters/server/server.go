@@ -3,20 +3,43 @@ package server
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/vizdos-enterprises/go-lti/internal/adapters/server/middleware"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
 )
 
 type Server struct {
-	launcher    lti_ports.Launcher
-	verifier    lti_ports.AsymetricVerifier
-	impostering lti_ports.Impostering
-	mux         http.ServeMux
+	launcher     lti_ports.Launcher
+	verifier     lti_ports.AsymetricVerifier
+	impostering  lti_ports.Impostering
+	registration lti_ports.DynamicRegistration
+	revoker      lti_ports.SessionRevoker
+	claimStore   lti_ports.EphemeralStore
+	routes       lti_domain.Routes
+	cookies      lti_domain.CookiePolicy
+	mux          http.ServeMux
+}
+
+// withConfig makes the route configuration, LTI version and cookie policy
+// available to every adapter, and the session revoker and claim store to the
+// session verifiers.
+func withConfig(routes lti_domain.Routes, version string, cookies lti_domain.CookiePolicy, revoker lti_ports.SessionRevoker, claimStore lti_ports.EphemeralStore, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := lti_domain.ContextWithRoutes(r.Context(), routes)
+		ctx = lti_domain.ContextWithLTIVersion(ctx, version)
+		ctx = lti_domain.ContextWithCookiePolicy(ctx, cookies)
+		if revoker != nil {
+			ctx = middleware.ContextWithRevoker(ctx, revoker)
+		}
+		if claimStore != nil {
+			ctx = middleware.ContextWithSessionClaimStore(ctx, claimStore)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
 
 func withTrace(next http.Handler) http.Handler {
@@ -39,20 +62,37 @@ func withTrace(next http.Handler) http.Handler {
 
 func (s *Server) CreateRoutes(opts ...lti_ports.HTTPRouteOption) http.Handler {
 	mux := http.NewServeMux()
+	routes := s.routes
+	version := s.launcher.GetLTIVersion()
 
-	mux.Handle("/lti/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-	}))
+	if routes.Prefix != "" {
+		mux.Handle(routes.Prefix+"/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+	}
 
 	if s.impostering != nil {
-		mux.HandleFunc("/lti/imposter", s.impostering.HandleImposterLaunch)
+		mux.HandleFunc(routes.Path("imposter"), s.impostering.HandleImposterLaunch)
+	}
+
+	mux.Handle(routes.Auth()+"/", http.StripPrefix(routes.Auth(), http.HandlerFunc(s.launcher.HandleAuthFallback)))
+	if refresher, ok := s.launcher.(lti_ports.SessionRefresher); ok {
+		mux.Handle(routes.Refresh(), middleware.VerifySessionRefresh(s.verifier, s.launcher.GetAudience(), http.HandlerFunc(refresher.HandleRefresh)))
+	}
+	mux.Handle(routes.Logout(), middleware.VerifySessionRefresh(s.verifier, s.launcher.GetAudience(), http.HandlerFunc(s.handleLogout)))
+	mux.HandleFunc(routes.Versioned(version, "swap"), s.launcher.HandleCodeSwap)
+	mux.HandleFunc(routes.Versioned(version, "launch"), s.launcher.HandleLaunch)
+	// Deep target_link_uris such as /launch/quiz are posted under the launch URL.
+	mux.HandleFunc(routes.Versioned(version, "launch")+"/", s.launcher.HandleLaunch)
+	mux.HandleFunc(routes.Versioned(version, "oidc"), s.launcher.HandleOIDC)
+	if s.registration != nil {
+		tool := s.toolRegistration()
+		mux.HandleFunc(routes.Path("register"), func(w http.ResponseWriter, r *http.Request) {
+			s.registration.HandleRegistration(w, r, tool)
+		})
 	}
 
-	mux.Handle("/lti/auth/", http.StripPrefix("/lti/auth", http.HandlerFunc(s.launcher.HandleAuthFallback)))
-	mux.HandleFunc(fmt.Sprintf("/lti/%s/swap", s.launcher.GetLTIVersion()), s.launcher.HandleCodeSwap)
-	mux.HandleFunc(fmt.Sprintf("/lti/%s/launch", s.launcher.GetLTIVersion()), s.launcher.HandleLaunch)
-	mux.HandleFunc(fmt.Sprintf("/lti/%s/oidc", s.launcher.GetLTIVersion()), s.launcher.HandleOIDC)
-	mux.HandleFunc("/lti/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc(routes.Path(".well-known", "jwks.json"), func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		jwks, err := s.verifier.JWKs(r.Context())
 		if err != nil {
@@ -72,7 +112,7 @@ func (s *Server) CreateRoutes(opts ...lti_ports.HTTPRouteOption) http.Handler {
 	}
 
 	if s.verifier != nil {
-		mux.HandleFunc("/lti/keys.json", func(w http.ResponseWriter, r *http.Request) {
+		mux.HandleFunc(routes.Path("keys.json"), func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
 			keys, err := s.verifier.JWKs(r.Context())
 			if err != nil {
@@ -88,9 +128,27 @@ func (s *Server) CreateRoutes(opts ...lti_ports.HTTPRouteOption) http.Handler {
 		})
 	}
 
-	return withTrace(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	return withTrace(withConfig(routes, version, s.cookies, s.revoker, s.claimStore, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		mux.ServeHTTP(w, r)
-	}))
+	})))
+}
+
+// toolRegistration describes this server's LTI endpoints for Dynamic Registration.
+func (s *Server) toolRegistration() lti_domain.ToolRegistration {
+	desc, ok := s.launcher.(lti_ports.LauncherDescriptor)
+	if !ok {
+		panic("lti: dynamic registration requires a launcher implementing lti_ports.LauncherDescriptor")
+	}
+
+	base := strings.TrimRight(desc.GetBaseURL(), "/")
+	version := s.launcher.GetLTIVersion()
+	return lti_domain.ToolRegistration{
+		BaseURL:      base,
+		OIDCLoginURL: base + s.routes.Versioned(version, "oidc"),
+		LaunchURL:    base + s.routes.Versioned(version, "launch"),
+		JWKSURL:      base + s.routes.Path(".well-known", "jwks.json"),
+		Messages:     desc.GetEnabledServices(),
+	}
 }
 
 func (s *Server) GetVerifier() lti_ports.Verifier {
@@ -101,6 +159,10 @@ func (s *Server) GetLauncher() lti_ports.Launcher {
 	return s.launcher
 }
 
+func (s *Server) GetRoutes() lti_domain.Routes {
+	return s.routes
+}
+
 func WithProtectedRoutes(routes ...lti_ports.ProtectedRoute) lti_ports.HTTPRouteOption {
 	return func(s lti_ports.Server, m *http.ServeMux) {
 		for _, route := range routes {
@@ -110,13 +172,23 @@ func WithProtectedRoutes(routes ...lti_ports.ProtectedRoute) lti_ports.HTTPRoute
 				vFunc = route.Verifier
 			}
 
+			handler := route.Handler
+			if route.RequireDeepLinkContext {
+				handler = middleware.RequireDeepLink(s.GetVerifier())(handler)
+			}
+
+			if len(route.LaunchTypes) > 0 {
+				handler = middleware.RequireLaunchType(route.LaunchTypes...)(handler)
+			}
+
 			// First wrap the handler with RequireRole
-			roleChecked := middleware.RequireRole(route.Role...)(route.Handler)
+			roleChecked := middleware.RequireRole(route.Role...)(handler)
 
 			// Then wrap the result with the verifier
 			protected := vFunc(s.GetVerifier(), s.GetLauncher().GetAudience(), route.AllowImpostering, roleChecked)
-			path := fmt.Sprintf("/lti/app%s", route.Path)
-			strip := fmt.Sprintf("/lti/app%s", strings.TrimRight(route.Path, "/"))
+			app := s.GetRoutes().App()
+			path := app + route.Path
+			strip := app + strings.TrimRight(route.Path, "/")
 			m.Handle(path, http.StripPrefix(strip, protected))
 		}
 	}
@@ -0,0 +1,168 @@
+package server_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/crypto"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/deeplinking"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/server"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/session"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_deeplink"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_http"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// sessionVerifier injects session as the verified LTI session.
+func sessionVerifier(session *lti_domain.LTIJWT) lti_ports.VerifyTokenFunc {
+	return func(_ lti_ports.Verifier, _ []string, _ bool, next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(lti_domain.ContextWithLTI(r.Context(), session)))
+		})
+	}
+}
+
+type deepLinkRouteResult struct {
+	code        int
+	called      bool
+	hasDeepLink bool
+	errParam    string
+}
+
+func serveDeepLinkRoute(t *testing.T, requireDeepLink bool, session *lti_domain.LTIJWT, deepLinkCookie string) deepLinkRouteResult {
+	t.Helper()
+
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	signer := crypto.NewRS256("tool-key", priv, &priv.PublicKey, "https://tool.example")
+	s := server.NewServer(server.WithLauncher(&fakeLauncher{}), server.WithVerifier(signer))
+
+	if deepLinkCookie == "sign" {
+		signed, err := signer.Sign(&lti_domain.DeepLinkContext{
+			ReturnURL:   "https://lms.example/return",
+			AttachedKID: "session-1",
+		}, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deepLinkCookie = signed
+	}
+
+	var res deepLinkRouteResult
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res.called = true
+		_, res.hasDeepLink = lti_deeplink.DeepLinkFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux := s.CreateRoutes(lti_http.WithProtectedRoutes(
+		lti_ports.ProtectedRoute{
+			Path:                   "/respond",
+			Handler:                handler,
+			Verifier:               sessionVerifier(session),
+			RequireDeepLinkContext: requireDeepLink,
+		},
+	))
+
+	req := httptest.NewRequest(http.MethodGet, "/lti/app/respond", nil)
+	if deepLinkCookie != "" {
+		req.AddCookie(&http.Cookie{Name: deeplinking.ContextKey_DeepLink, Value: deepLinkCookie})
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	res.code = w.Code
+	if loc := w.Header().Get("Location"); loc != "" {
+		u, _ := url.Parse(loc)
+		if u.Path != "/lti/auth/error" {
+			t.Fatalf("expected redirect to error page, got %q", u.Path)
+		}
+		res.errParam = u.Query().Get("err")
+	}
+	return res
+}
+
+func deepLinkSession() *lti_domain.LTIJWT {
+	return &lti_domain.LTIJWT{
+		RegisteredClaims: jwt.RegisteredClaims{ID: "jti-1"},
+		SessionID:        "session-1",
+		LaunchType:       lti_domain.LTIService_DeepLink,
+	}
+}
+
+func TestRequireDeepLinkContext_AcceptsBoundCookie(t *testing.T) {
+	res := serveDeepLinkRoute(t, true, deepLinkSession(), "sign")
+
+	if res.code != http.StatusOK || !res.called {
+		t.Fatalf("expected handler to be called, got %d (err=%q)", res.code, res.errParam)
+	}
+	if !res.hasDeepLink {
+		t.Fatalf("expected deep link context on request")
+	}
+}
+
+func TestRequireDeepLinkContext_AcceptsRefreshedSession(t *testing.T) {
+	claims := deepLinkSession()
+	if _, err := session.Stamp(claims, lti_domain.DefaultPolicy(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if claims.ID == "jti-1" {
+		t.Fatalf("expected refresh to issue a new jti")
+	}
+
+	res := serveDeepLinkRoute(t, true, claims, "sign")
+
+	if res.code != http.StatusOK || !res.called {
+		t.Fatalf("expected refreshed session to keep its deep link context, got %d (err=%q)", res.code, res.errParam)
+	}
+}
+
+func TestRequireDeepLinkContext_RejectsResourceLinkSession(t *testing.T) {
+	session := deepLinkSession()
+	session.LaunchType = lti_domain.LTIService_ResourceLink
+
+	res := serveDeepLinkRoute(t, true, session, "sign")
+
+	if res.called {
+		t.Fatalf("expected handler not to be called")
+	}
+	if res.code != http.StatusTemporaryRedirect || res.errParam == "" {
+		t.Fatalf("expected redirect to error page with message, got %d (err=%q)", res.code, res.errParam)
+	}
+}
+
+func TestRequireDeepLinkContext_RejectsMissingCookie(t *testing.T) {
+	res := serveDeepLinkRoute(t, true, deepLinkSession(), "")
+
+	if res.called || res.code != http.StatusTemporaryRedirect {
+		t.Fatalf("expected redirect without calling handler, got %d", res.code)
+	}
+}
+
+func TestRequireDeepLinkContext_RejectsCookieFromOtherSession(t *testing.T) {
+	session := deepLinkSession()
+	session.SessionID = "session-2"
+
+	res := serveDeepLinkRoute(t, true, session, "sign")
+
+	if res.called || res.code != http.StatusTemporaryRedirect {
+		t.Fatalf("expected redirect without calling handler, got %d", res.code)
+	}
+}
+
+func TestRequireDeepLinkContext_NotRequiredIgnoresCookie(t *testing.T) {
+	res := serveDeepLinkRoute(t, false, deepLinkSession(), "garbage")
+
+	if res.code != http.StatusOK || !res.called {
+		t.Fatalf("expected handler to be called, got %d", res.code)
+	}
+	if res.hasDeepLink {
+		t.Fatalf("expected no deep link context when the route does not require it")
+	}
+}
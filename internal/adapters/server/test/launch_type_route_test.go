@@ -0,0 +1,53 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/server"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_http"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+func serveGraderRoute(t *testing.T, launchType lti_domain.LTIService) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+
+	called := false
+	s := server.NewServer(server.WithLauncher(&fakeLauncher{}), server.WithVerifier(&fakeVerifier{}))
+	mux := s.CreateRoutes(lti_http.WithProtectedRoutes(lti_ports.ProtectedRoute{
+		Path: "/grader/",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.WriteHeader(http.StatusOK)
+		}),
+		Verifier:    sessionVerifier(&lti_domain.LTIJWT{LaunchType: launchType}),
+		LaunchTypes: []lti_domain.LTIService{lti_domain.LTIService_SubmissionReview},
+	}))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/lti/app/grader/", nil))
+	return w, called
+}
+
+func TestLaunchTypes_AllowsMatchingLaunch(t *testing.T) {
+	w, called := serveGraderRoute(t, lti_domain.LTIService_SubmissionReview)
+
+	if w.Code != http.StatusOK || !called {
+		t.Fatalf("expected submission review session to reach the handler, got %d", w.Code)
+	}
+}
+
+func TestLaunchTypes_RejectsOtherLaunches(t *testing.T) {
+	w, called := serveGraderRoute(t, lti_domain.LTIService_ResourceLink)
+
+	if called {
+		t.Fatal("expected resource link session to be rejected")
+	}
+	loc, _ := url.Parse(w.Header().Get("Location"))
+	if w.Code != http.StatusTemporaryRedirect || loc.Path != "/lti/auth/error" || loc.Query().Get("err") != "launch type" {
+		t.Fatalf("expected redirect to the error page, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+}
@@ -0,0 +1,143 @@
+package server_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/crypto"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/revocation"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/server"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_http"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+func setupLogout(t *testing.T) (http.Handler, string) {
+	t.Helper()
+
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	signer := crypto.NewRS256("tool-key", priv, &priv.PublicKey, "https://tool.example")
+	revoker := revocation.NewRevoker(revocation.WithStore(&lti_testadapters.FakeRegistry{}))
+
+	s := server.NewServer(
+		server.WithLauncher(&fakeLauncher{}),
+		server.WithVerifier(signer),
+		server.WithSessionRevoker(revoker),
+	)
+	mux := s.CreateRoutes(lti_http.WithProtectedRoutes(lti_ports.ProtectedRoute{
+		Path: "/home",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	}))
+
+	start := time.Now().Add(-time.Minute)
+	signed, err := signer.Sign(lti_domain.LTIJWT{
+		SessionID:    "session-1",
+		SessionStart: jwt.NewNumericDate(start),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti-1",
+			Audience:  jwt.ClaimStrings{"aud"},
+			IssuedAt:  jwt.NewNumericDate(start),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mux, signed
+}
+
+func getHome(mux http.Handler, signed string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/lti/app/home", nil)
+	req.AddCookie(&http.Cookie{Name: lti_domain.ContextKey_Session, Value: signed})
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+func TestLogout_RevokesSessionAndClearsCookies(t *testing.T) {
+	mux, signed := setupLogout(t)
+
+	if w := getHome(mux, signed); w.Code != http.StatusOK {
+		t.Fatalf("expected session to be valid before logout, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/lti/auth/logout", nil)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.AddCookie(&http.Cookie{Name: lti_domain.ContextKey_SessionRefresh, Value: signed})
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 2 {
+		t.Fatalf("expected both session cookies to be cleared, got %d", len(cookies))
+	}
+	for _, c := range cookies {
+		if c.MaxAge >= 0 || c.Value != "" {
+			t.Errorf("expected cookie %s to be expired, got %+v", c.Name, c)
+		}
+	}
+
+	w = getHome(mux, signed)
+	if w.Code != http.StatusTemporaryRedirect || !strings.Contains(w.Header().Get("Location"), "session+revoked") {
+		t.Fatalf("expected revoked session to be redirected to the error page, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+}
+
+func TestLogout_RequiresPost(t *testing.T) {
+	mux, signed := setupLogout(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/lti/auth/logout", nil)
+	req.AddCookie(&http.Cookie{Name: lti_domain.ContextKey_SessionRefresh, Value: signed})
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+	if w := getHome(mux, signed); w.Code != http.StatusOK {
+		t.Fatalf("expected session to stay valid, got %d", w.Code)
+	}
+}
+
+func TestLogout_RejectsCrossSiteCookiePost(t *testing.T) {
+	mux, signed := setupLogout(t)
+
+	// A form on another site can send the SameSite=None cookie but not the header.
+	req := httptest.NewRequest(http.MethodPost, "/lti/auth/logout", nil)
+	req.AddCookie(&http.Cookie{Name: lti_domain.ContextKey_SessionRefresh, Value: signed})
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := getHome(mux, signed); w.Code != http.StatusOK {
+		t.Fatalf("expected session to stay valid, got %d", w.Code)
+	}
+}
+
+func TestLogout_BearerSkipsRequestedWith(t *testing.T) {
+	mux, signed := setupLogout(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/lti/auth/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+}
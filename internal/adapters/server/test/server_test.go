@@ -2,11 +2,16 @@ package server_test
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/crypto"
 	"github.com/vizdos-enterprises/go-lti/internal/adapters/server"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_http"
@@ -96,6 +101,22 @@ func TestCreateRoutes_BasicRoutes(t *testing.T) {
 	}
 }
 
+func TestCreateRoutes_LaunchServesTargetLinkPaths(t *testing.T) {
+	for _, path := range []string{"/lti/1.3/launch/quiz", "/lti/1.3/launch/quiz/42"} {
+		t.Run(path, func(t *testing.T) {
+			launcher := &fakeLauncher{}
+			mux := server.NewServer(server.WithLauncher(launcher), server.WithVerifier(&fakeVerifier{})).CreateRoutes()
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+
+			if w.Code != http.StatusOK || !launcher.launchCalled {
+				t.Fatalf("expected the launch handler to serve %s, got %d", path, w.Code)
+			}
+		})
+	}
+}
+
 func TestCreateRoutes_WithProtectedRoutes_CustomVerifier(t *testing.T) {
 	launcher := &fakeLauncher{}
 	verifier := &fakeVerifier{}
@@ -139,3 +160,123 @@ func TestCreateRoutes_WithProtectedRoutes_CustomVerifier(t *testing.T) {
 		t.Fatalf("expected 202 from protected route, got %d", w.Result().StatusCode)
 	}
 }
+
+func TestCreateRoutes_WithRoutes_MountsUnderPrefix(t *testing.T) {
+	launcher := &fakeLauncher{}
+	verifier := &fakeVerifier{}
+	s := server.NewServer(
+		server.WithLauncher(launcher),
+		server.WithVerifier(verifier),
+		server.WithRoutes(lti_domain.Routes{Prefix: "/integrations/lms/", AppPath: "portal", AuthPath: "/signin"}),
+	)
+
+	var seen lti_domain.Routes
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = lti_domain.RoutesFromContext(r.Context())
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	mux := s.CreateRoutes(lti_http.WithProtectedRoutes(
+		lti_ports.ProtectedRoute{Path: "/test", Handler: handler, Verifier: sessionVerifier(&lti_domain.LTIJWT{})},
+		lti_ports.ProtectedRoute{Path: "/default", Handler: handler},
+	))
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantCalled *bool
+	}{
+		{"/integrations/lms/1.3/launch", http.StatusOK, &launcher.launchCalled},
+		{"/integrations/lms/1.3/oidc", http.StatusOK, &launcher.oidcCalled},
+		{"/integrations/lms/signin/verify", http.StatusOK, &launcher.fallbackCalled},
+		{"/integrations/lms/portal/test", http.StatusAccepted, nil},
+		{"/integrations/lms/nonexistent", http.StatusNotFound, nil},
+		{"/lti/1.3/launch", http.StatusNotFound, nil},
+		{"/lti/app/test", http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantCalled != nil && !*tt.wantCalled {
+				t.Fatalf("expected handler for %s to be called", tt.path)
+			}
+		})
+	}
+
+	if seen.App() != "/integrations/lms/portal" || seen.Auth() != "/integrations/lms/signin" {
+		t.Fatalf("expected routes in request context, got %+v", seen)
+	}
+
+	// The default verifier sends users without a session to the configured error page.
+	req := httptest.NewRequest(http.MethodGet, "/integrations/lms/portal/default", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTemporaryRedirect {
+		t.Fatalf("expected redirect, got %d", w.Code)
+	}
+	if loc := w.Header().Get("Location"); !strings.HasPrefix(loc, "/integrations/lms/signin/error?") {
+		t.Fatalf("expected redirect to configured error page, got %q", loc)
+	}
+}
+
+func TestCreateRoutes_WithCookiePolicy_PerServer(t *testing.T) {
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	signer := crypto.NewRS256("tool-key", priv, &priv.PublicKey, "https://tool.example")
+
+	newServer := func(prefix string) http.Handler {
+		s := server.NewServer(
+			server.WithLauncher(&fakeLauncher{}),
+			server.WithVerifier(signer),
+			server.WithCookiePolicy(lti_domain.CookiePolicy{NamePrefix: prefix}),
+		)
+		return s.CreateRoutes(lti_http.WithProtectedRoutes(
+			lti_ports.ProtectedRoute{
+				Path: "/test",
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusAccepted)
+				}),
+			},
+		))
+	}
+
+	toolA := newServer("a_")
+	toolB := newServer("b_")
+
+	signed, err := signer.Sign(&lti_domain.LTIJWT{
+		RegisteredClaims: jwt.RegisteredClaims{Audience: []string{"aud"}},
+	}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A session cookie for tool A is only honoured by tool A.
+	cookie := &http.Cookie{Name: "a_" + lti_domain.ContextKey_Session, Value: signed}
+
+	for _, tt := range []struct {
+		name       string
+		handler    http.Handler
+		wantStatus int
+	}{
+		{"tool A", toolA, http.StatusAccepted},
+		{"tool B", toolB, http.StatusTemporaryRedirect},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/lti/app/test", nil)
+			req.AddCookie(cookie)
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package servicetoken
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.ServiceTokenProvider = (*ClientCredentialsProvider)(nil)
+
+// ClientCredentialsProvider implements the LTI Advantage client_credentials
+// grant, authenticating with an RFC 7523 JWT client assertion.
+type ClientCredentialsProvider struct {
+	signer       lti_ports.AsymetricSigner
+	httpClient   *http.Client
+	clock        lti_ports.Clock
+	logger       lti_ports.Logger
+	expiryMargin time.Duration
+
+	mu       sync.Mutex
+	cache    map[string]cachedToken
+	inflight map[string]*tokenCall
+}
+
+type cachedToken struct {
+	accessToken string
+	expiresAt   time.Time
+}
+
+// tokenCall is a token request other callers for the same key wait on.
+type tokenCall struct {
+	done        chan struct{}
+	accessToken string
+	err         error
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+func makeCacheKey(dep lti_domain.Deployment, scopes []string) string {
+	sorted := slices.Clone(scopes)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+	return dep.GetLTITokenEndpoint() + "|" + dep.GetLTIClientID() + "|" + dep.GetLTIDeploymentID() + "|" + strings.Join(sorted, " ")
+}
+
+// AccessToken returns a cached token for the deployment and scope set, or
+// requests one. Concurrent misses for the same key share a single request.
+func (p *ClientCredentialsProvider) AccessToken(ctx context.Context, dep lti_domain.Deployment, scopes []string) (string, error) {
+	key := makeCacheKey(dep, scopes)
+	now := p.clock.Now()
+
+	p.mu.Lock()
+	if cached, ok := p.cache[key]; ok && now.Add(p.expiryMargin).Before(cached.expiresAt) {
+		p.mu.Unlock()
+		return cached.accessToken, nil
+	}
+	if call, ok := p.inflight[key]; ok {
+		p.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.accessToken, call.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	call := &tokenCall{done: make(chan struct{})}
+	p.inflight[key] = call
+	p.mu.Unlock()
+
+	call.accessToken, call.err = p.fetchToken(ctx, dep, scopes, key, now)
+
+	p.mu.Lock()
+	delete(p.inflight, key)
+	p.mu.Unlock()
+	close(call.done)
+
+	return call.accessToken, call.err
+}
+
+// fetchToken requests a token and caches it, dropping expired entries so
+// the cache only holds live tokens.
+func (p *ClientCredentialsProvider) fetchToken(ctx context.Context, dep lti_domain.Deployment, scopes []string, key string, now time.Time) (string, error) {
+	tok, err := p.requestToken(ctx, dep, scopes)
+	if err != nil {
+		p.logger.Error("failed to obtain service token", "deployment", dep.GetDeploymentID(), "error", err)
+		return "", err
+	}
+
+	expiresIn := time.Duration(tok.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		// The platform did not tell us; don't cache beyond a single use.
+		return tok.AccessToken, nil
+	}
+
+	p.mu.Lock()
+	for k, cached := range p.cache {
+		if !now.Before(cached.expiresAt) {
+			delete(p.cache, k)
+		}
+	}
+	p.cache[key] = cachedToken{
+		accessToken: tok.AccessToken,
+		expiresAt:   now.Add(expiresIn),
+	}
+	p.mu.Unlock()
+
+	return tok.AccessToken, nil
+}
+
+func (p *ClientCredentialsProvider) requestToken(ctx context.Context, dep lti_domain.Deployment, scopes []string) (*tokenResponse, error) {
+	tokenEndpoint := dep.GetLTITokenEndpoint()
+	if tokenEndpoint == "" {
+		return nil, fmt.Errorf("%w: deployment has no token endpoint", lti_domain.ErrServiceTokenRequest)
+	}
+
+	now := p.clock.Now().UTC()
+	assertion, err := p.signer.Sign(jwt.MapClaims{
+		"iss": dep.GetLTIClientID(),
+		"sub": dep.GetLTIClientID(),
+		"aud": tokenEndpoint,
+		"iat": now.Unix(),
+		"exp": now.Add(5 * time.Minute).Unix(),
+		"jti": rand.Text(),
+	}, 5*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("sign client assertion: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	form.Set("client_assertion", assertion)
+	form.Set("scope", strings.Join(scopes, " "))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", lti_domain.ErrServiceTokenRequest, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: token endpoint returned %d", lti_domain.ErrServiceTokenRequest, resp.StatusCode)
+	}
+
+	var tok tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return nil, fmt.Errorf("%w: %w", lti_domain.ErrServiceTokenRequest, err)
+	}
+	if tok.AccessToken == "" {
+		return nil, fmt.Errorf("%w: empty access token", lti_domain.ErrServiceTokenRequest)
+	}
+
+	return &tok, nil
+}
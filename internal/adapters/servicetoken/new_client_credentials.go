@@ -0,0 +1,66 @@
+package servicetoken
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/clock"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_logger"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+func NewClientCredentials(opts ...lti_ports.ServiceTokenOption) lti_ports.ServiceTokenProvider {
+	p := &ClientCredentialsProvider{
+		httpClient:   &http.Client{Timeout: 15 * time.Second},
+		clock:        clock.NewSystemClock(),
+		logger:       lti_logger.NewNoopLogger(),
+		expiryMargin: 30 * time.Second,
+		cache:        make(map[string]cachedToken),
+		inflight:     make(map[string]*tokenCall),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.signer == nil {
+		panic("a signer is required for a service token provider. Call with WithSigner")
+	}
+
+	return p
+}
+
+func WithSigner(signer lti_ports.AsymetricSigner) lti_ports.ServiceTokenOption {
+	return func(s lti_ports.ServiceTokenProvider) {
+		cast := s.(*ClientCredentialsProvider)
+		cast.signer = signer
+	}
+}
+
+func WithHTTPClient(client *http.Client) lti_ports.ServiceTokenOption {
+	return func(s lti_ports.ServiceTokenProvider) {
+		cast := s.(*ClientCredentialsProvider)
+		cast.httpClient = client
+	}
+}
+
+func WithClock(clock lti_ports.Clock) lti_ports.ServiceTokenOption {
+	return func(s lti_ports.ServiceTokenProvider) {
+		cast := s.(*ClientCredentialsProvider)
+		cast.clock = clock
+	}
+}
+
+// WithExpiryMargin sets how long before expiry a cached token is considered stale.
+func WithExpiryMargin(margin time.Duration) lti_ports.ServiceTokenOption {
+	return func(s lti_ports.ServiceTokenProvider) {
+		cast := s.(*ClientCredentialsProvider)
+		cast.expiryMargin = margin
+	}
+}
+
+func WithLogger(logger lti_ports.Logger) lti_ports.ServiceTokenOption {
+	return func(s lti_ports.ServiceTokenProvider) {
+		cast := s.(*ClientCredentialsProvider)
+		cast.logger = logger
+	}
+}
@@ -0,0 +1,215 @@
+package servicetoken_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/crypto"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/servicetoken"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_testadapters"
+)
+
+type tokenEndpoint struct {
+	server    *httptest.Server
+	calls     atomic.Int32
+	lastScope atomic.Value
+	status    int
+	delay     time.Duration
+}
+
+func newTokenEndpoint(t *testing.T, verifier lti_ports.Verifier) *tokenEndpoint {
+	t.Helper()
+	e := &tokenEndpoint{status: http.StatusOK}
+
+	e.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := e.calls.Add(1)
+		time.Sleep(e.delay)
+		if e.status != http.StatusOK {
+			w.WriteHeader(e.status)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "bad form", http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("client_assertion_type") != "urn:ietf:params:oauth:client-assertion-type:jwt-bearer" {
+			http.Error(w, "bad assertion type", http.StatusBadRequest)
+			return
+		}
+
+		claims := jwt.MapClaims{}
+		if _, err := verifier.Verify(r.FormValue("client_assertion"), &claims); err != nil {
+			http.Error(w, "bad assertion", http.StatusUnauthorized)
+			return
+		}
+		aud, _ := claims.GetAudience()
+		if len(aud) != 1 || aud[0] != e.server.URL {
+			http.Error(w, "bad audience", http.StatusUnauthorized)
+			return
+		}
+
+		e.lastScope.Store(r.FormValue("scope"))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "token-" + string(rune('0'+n)),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(e.server.Close)
+	return e
+}
+
+func newSigner() lti_ports.AsymetricSignerVerifier {
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	return crypto.NewRS256("tool-key", priv, &priv.PublicKey, "https://tool.example")
+}
+
+func setupProvider(t *testing.T) (lti_ports.ServiceTokenProvider, *tokenEndpoint, *lti_testadapters.FakeClock, lti_domain.Deployment) {
+	t.Helper()
+	signer := newSigner()
+
+	endpoint := newTokenEndpoint(t, signer)
+	clock := lti_testadapters.NewFakeClock(time.Now())
+
+	provider := servicetoken.NewClientCredentials(
+		servicetoken.WithSigner(signer),
+		servicetoken.WithClock(clock),
+	)
+
+	dep := lti_domain.BaseLTIDeployment{
+		ClientID:      "client1",
+		DeploymentID:  "dep1",
+		TokenEndpoint: endpoint.server.URL,
+	}
+	return provider, endpoint, clock, dep
+}
+
+func TestAccessToken_CachesPerScopeSet(t *testing.T) {
+	provider, endpoint, _, dep := setupProvider(t)
+	ctx := context.Background()
+
+	first, err := provider.AccessToken(ctx, dep, []string{lti_domain.AGSScope_Score, lti_domain.AGSScope_LineItem})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Same scopes in a different order must hit the cache.
+	second, err := provider.AccessToken(ctx, dep, []string{lti_domain.AGSScope_LineItem, lti_domain.AGSScope_Score})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first != second {
+		t.Errorf("expected cached token %q, got %q", first, second)
+	}
+	if endpoint.calls.Load() != 1 {
+		t.Fatalf("expected 1 token request, got %d", endpoint.calls.Load())
+	}
+
+	if _, err := provider.AccessToken(ctx, dep, []string{lti_domain.AGSScope_Score}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if endpoint.calls.Load() != 2 {
+		t.Fatalf("expected a new token request for a different scope set, got %d", endpoint.calls.Load())
+	}
+	if got := endpoint.lastScope.Load(); got != lti_domain.AGSScope_Score {
+		t.Errorf("expected scope %q, got %q", lti_domain.AGSScope_Score, got)
+	}
+}
+
+func TestAccessToken_RefreshesAfterExpiry(t *testing.T) {
+	provider, endpoint, clock, dep := setupProvider(t)
+	ctx := context.Background()
+
+	first, _ := provider.AccessToken(ctx, dep, []string{lti_domain.AGSScope_Score})
+
+	clock.Advance(59*time.Minute + 45*time.Second)
+	second, err := provider.AccessToken(ctx, dep, []string{lti_domain.AGSScope_Score})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if first == second {
+		t.Errorf("expected a refreshed token inside the expiry margin")
+	}
+	if endpoint.calls.Load() != 2 {
+		t.Fatalf("expected 2 token requests, got %d", endpoint.calls.Load())
+	}
+}
+
+func TestAccessToken_EndpointError(t *testing.T) {
+	provider, endpoint, _, dep := setupProvider(t)
+	endpoint.status = http.StatusUnauthorized
+
+	_, err := provider.AccessToken(context.Background(), dep, []string{lti_domain.AGSScope_Score})
+	if !errors.Is(err, lti_domain.ErrServiceTokenRequest) {
+		t.Fatalf("expected ErrServiceTokenRequest, got %v", err)
+	}
+}
+
+func TestAccessToken_MissingTokenEndpoint(t *testing.T) {
+	provider, endpoint, _, _ := setupProvider(t)
+
+	_, err := provider.AccessToken(context.Background(), lti_domain.BaseLTIDeployment{ClientID: "client1"}, []string{lti_domain.AGSScope_Score})
+	if !errors.Is(err, lti_domain.ErrServiceTokenRequest) {
+		t.Fatalf("expected ErrServiceTokenRequest, got %v", err)
+	}
+	if endpoint.calls.Load() != 0 {
+		t.Errorf("expected no token request, got %d", endpoint.calls.Load())
+	}
+}
+
+func TestAccessToken_SeparatesTokenEndpoints(t *testing.T) {
+	signer := newSigner()
+	provider := servicetoken.NewClientCredentials(servicetoken.WithSigner(signer))
+	first, second := newTokenEndpoint(t, signer), newTokenEndpoint(t, signer)
+	ctx := context.Background()
+
+	// Two platforms that happen to issue the same client and deployment IDs.
+	for _, endpoint := range []*tokenEndpoint{first, second} {
+		dep := lti_domain.BaseLTIDeployment{ClientID: "client1", DeploymentID: "dep1", TokenEndpoint: endpoint.server.URL}
+		if _, err := provider.AccessToken(ctx, dep, []string{lti_domain.AGSScope_Score}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if first.calls.Load() != 1 || second.calls.Load() != 1 {
+		t.Fatalf("expected each platform to issue its own token, got %d and %d", first.calls.Load(), second.calls.Load())
+	}
+}
+
+func TestAccessToken_SharesConcurrentRequests(t *testing.T) {
+	provider, endpoint, _, dep := setupProvider(t)
+	endpoint.delay = 50 * time.Millisecond
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 8)
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tokens[i], _ = provider.AccessToken(context.Background(), dep, []string{lti_domain.AGSScope_Score})
+		}()
+	}
+	wg.Wait()
+
+	if endpoint.calls.Load() != 1 {
+		t.Fatalf("expected concurrent misses to share one token request, got %d", endpoint.calls.Load())
+	}
+	for _, tok := range tokens {
+		if tok != tokens[0] || tok == "" {
+			t.Fatalf("expected every caller to get the same token, got %v", tokens)
+		}
+	}
+}
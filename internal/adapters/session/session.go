@@ -0,0 +1,86 @@
+// Package session issues the signed session token shared by every way a
+// launch can finish, so refresh rules apply the same to all of them.
+package session
+
+import (
+	"crypto/rand"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// Sign stamps claims with a fresh expiry and token ID and signs them. The
+// session ID and start are kept from earlier tokens, so the expiry never passes
+// policy.MaxSessionLifetime; once it would, ErrSessionLifetimeExceeded is
+// returned.
+func Sign(signer lti_ports.Signer, claims lti_domain.LTIJWT, policy lti_domain.Policy, now time.Time) (string, time.Time, error) {
+	expires, err := Stamp(&claims, policy, now)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	signed, err := signer.Sign(claims, expires.Sub(now))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expires, nil
+}
+
+// Stamp sets the session and token IDs and times on claims in place, the way
+// Sign does, and returns the new expiry.
+func Stamp(claims *lti_domain.LTIJWT, policy lti_domain.Policy, now time.Time) (time.Time, error) {
+	policy = policy.WithDefaults()
+
+	start := now
+	switch {
+	case claims.SessionStart != nil:
+		start = claims.SessionStart.Time
+	case claims.IssuedAt != nil:
+		start = claims.IssuedAt.Time
+	}
+
+	expires := now.Add(policy.SessionTTL)
+	if limit := start.Add(policy.MaxSessionLifetime); expires.After(limit) {
+		expires = limit
+	}
+	if !expires.After(now) {
+		return time.Time{}, lti_domain.ErrSessionLifetimeExceeded
+	}
+
+	if claims.SessionID == "" {
+		claims.SessionID = rand.Text()
+	}
+	claims.ID = rand.Text()
+	claims.SessionStart = jwt.NewNumericDate(start)
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.NotBefore = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(expires)
+	return expires, nil
+}
+
+// SetCookies stores signed in the session cookie for appPath and in the
+// companion cookie the refresh and logout endpoints read.
+func SetCookies(w http.ResponseWriter, r *http.Request, signed, appPath string) {
+	cookies := lti_domain.CookiePolicyFromContext(r.Context())
+	auth := lti_domain.RoutesFromContext(r.Context()).Auth() + "/"
+
+	http.SetCookie(w, cookies.Cookie(lti_domain.ContextKey_Session, signed, appPath))
+	http.SetCookie(w, cookies.Cookie(lti_domain.ContextKey_SessionRefresh, signed, auth))
+}
+
+// ClearCookies expires the cookies set by SetCookies.
+func ClearCookies(w http.ResponseWriter, r *http.Request, appPath string) {
+	cookies := lti_domain.CookiePolicyFromContext(r.Context())
+	auth := lti_domain.RoutesFromContext(r.Context()).Auth() + "/"
+
+	for _, c := range []*http.Cookie{
+		cookies.Cookie(lti_domain.ContextKey_Session, "", appPath),
+		cookies.Cookie(lti_domain.ContextKey_SessionRefresh, "", auth),
+	} {
+		c.MaxAge = -1
+		http.SetCookie(w, c)
+	}
+}
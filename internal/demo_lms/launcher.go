@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -20,6 +22,23 @@ type DemoPlatform struct {
 	PrivateKey ed25519.PrivateKey
 	PublicKey  ed25519.PublicKey
 	KeyID      string
+
+	// RegistrationToken, when set, must be presented as a bearer token to the
+	// Dynamic Registration endpoint.
+	RegistrationToken string
+
+	mu         sync.Mutex
+	registered []RegisteredTool
+}
+
+// RegisteredTool is what the demo platform recorded from a Dynamic Registration request.
+type RegisteredTool struct {
+	ClientName       string
+	InitiateLoginURI string
+	RedirectURIs     []string
+	JWKSURI          string
+	Scope            string
+	Messages         []string
 }
 
 func NewDemoPlatform(baseURL string) *DemoPlatform {
@@ -38,10 +57,19 @@ func (d *DemoPlatform) JWKSURL() string {
 	return d.Issuer + "/jwks.json"
 }
 
+// RegisteredTools returns the tools registered through Dynamic Registration.
+func (d *DemoPlatform) RegisteredTools() []RegisteredTool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.registered)
+}
+
 func (d *DemoPlatform) Routes(toolBaseURL string) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/jwks.json", d.handleJWKS)
+	mux.HandleFunc("GET /.well-known/openid-configuration", d.handleOpenIDConfiguration)
+	mux.HandleFunc("POST /register", d.handleRegister)
 
 	mux.HandleFunc("/render", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -189,3 +217,79 @@ func (d *DemoPlatform) handleJWKS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (d *DemoPlatform) handleOpenIDConfiguration(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"issuer":                 d.Issuer,
+		"authorization_endpoint": d.Issuer + "/auth",
+		"token_endpoint":         d.Issuer + "/token",
+		"jwks_uri":               d.JWKSURL(),
+		"registration_endpoint":  d.Issuer + "/register",
+		"scopes_supported":       []string{"openid"},
+		"https://purl.imsglobal.org/spec/lti-platform-configuration": map[string]any{
+			"product_family_code": "demo",
+			"version":             "1.0",
+			"messages_supported": []map[string]any{
+				{"type": "LtiResourceLinkRequest"},
+				{"type": "LtiDeepLinkingRequest"},
+			},
+		},
+	})
+}
+
+func (d *DemoPlatform) handleRegister(w http.ResponseWriter, r *http.Request) {
+	if d.RegistrationToken != "" && r.Header.Get("Authorization") != "Bearer "+d.RegistrationToken {
+		http.Error(w, "invalid registration token", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		ClientName       string   `json:"client_name"`
+		InitiateLoginURI string   `json:"initiate_login_uri"`
+		RedirectURIs     []string `json:"redirect_uris"`
+		JWKSURI          string   `json:"jwks_uri"`
+		Scope            string   `json:"scope"`
+		ToolConfig       struct {
+			Messages []struct {
+				Type string `json:"type"`
+			} `json:"messages"`
+		} `json:"https://purl.imsglobal.org/spec/lti-tool-configuration"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid registration", http.StatusBadRequest)
+		return
+	}
+	if req.InitiateLoginURI == "" || len(req.RedirectURIs) == 0 || req.JWKSURI == "" {
+		http.Error(w, "incomplete registration", http.StatusBadRequest)
+		return
+	}
+
+	tool := RegisteredTool{
+		ClientName:       req.ClientName,
+		InitiateLoginURI: req.InitiateLoginURI,
+		RedirectURIs:     req.RedirectURIs,
+		JWKSURI:          req.JWKSURI,
+		Scope:            req.Scope,
+	}
+	for _, m := range req.ToolConfig.Messages {
+		tool.Messages = append(tool.Messages, m.Type)
+	}
+
+	d.mu.Lock()
+	d.registered = append(d.registered, tool)
+	d.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"client_id":                  d.ClientID,
+		"application_type":           "web",
+		"initiate_login_uri":         req.InitiateLoginURI,
+		"redirect_uris":              req.RedirectURIs,
+		"jwks_uri":                   req.JWKSURI,
+		"token_endpoint_auth_method": "private_key_jwt",
+		"https://purl.imsglobal.org/spec/lti-tool-configuration": map[string]any{
+			"deployment_id": d.DeploymentID,
+		},
+	})
+}
@@ -0,0 +1,39 @@
+package lti_ags
+
+import (
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/ags"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// NewAGS returns an Assignment and Grade Services client. Access tokens are
+// requested from each deployment's token endpoint, either through the provider
+// given to WithTokenProvider or one built from WithSigner.
+func NewAGS(opts ...lti_ports.AGSOption) lti_ports.AGS {
+	return ags.NewAGS(opts...)
+}
+
+// WithRegistry sets the registry used to resolve the session's deployment.
+func WithRegistry(registry lti_ports.Registry) lti_ports.AGSOption {
+	return ags.WithRegistry(registry)
+}
+
+// WithSigner sets the signer used for the OAuth2 client assertion. Its public
+// key must be published on the tool's JWKS URL.
+func WithSigner(signer lti_ports.AsymetricSigner) lti_ports.AGSOption {
+	return ags.WithSigner(signer)
+}
+
+// WithTokenProvider sets the provider used to obtain AGS access tokens.
+func WithTokenProvider(tokens lti_ports.ServiceTokenProvider) lti_ports.AGSOption {
+	return ags.WithTokenProvider(tokens)
+}
+
+func WithHTTPClient(client *http.Client) lti_ports.AGSOption {
+	return ags.WithHTTPClient(client)
+}
+
+func WithLogger(logger lti_ports.Logger) lti_ports.AGSOption {
+	return ags.WithLogger(logger)
+}
@@ -19,3 +19,21 @@ func NewRS256(keyID string, priv *rsa.PrivateKey, pub *rsa.PublicKey, issuer str
 func NewES256(keyID string, priv *ecdsa.PrivateKey, pub *ecdsa.PublicKey, issuer string) lti_ports.AsymetricSignerVerifier {
 	return crypto.NewES256(keyID, priv, pub, issuer)
 }
+
+// KeyRing signs with the active key and verifies and publishes every current
+// or retiring key, enabling zero-downtime key rotation.
+type KeyRing = crypto.KeyRing
+
+// KeyRingKey is one key in a KeyRing and its promotion/retirement window.
+type KeyRingKey = crypto.KeyRingKey
+
+type KeyRingOption = crypto.KeyRingOption
+
+func NewKeyRing(keys []KeyRingKey, opts ...KeyRingOption) *KeyRing {
+	return crypto.NewKeyRing(keys, opts...)
+}
+
+// WithKeyRingClock overrides the clock used to evaluate key windows.
+func WithKeyRingClock(clock lti_ports.Clock) KeyRingOption {
+	return crypto.WithKeyRingClock(clock)
+}
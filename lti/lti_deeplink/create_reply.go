@@ -41,7 +41,12 @@ func CreateReplyJWT(signer lti_ports.AsymetricSigner, ctx *lti_domain.DeepLinkCo
 
 func ReplyToDeeplink(w http.ResponseWriter, r *http.Request, signer lti_ports.AsymetricSigner, items []lti_domain.DeepLinkItem) error {
 	session, _ := lti_domain.LTIFromContext(r.Context())
-	deepLinkContext, _ := DeepLinkFromContext(r.Context())
+	deepLinkContext, ok := DeepLinkFromContext(r.Context())
+	if !ok || deepLinkContext == nil {
+		// The route was not registered with RequireDeepLinkContext.
+		http.Error(w, "deep link context missing", http.StatusBadRequest)
+		return lti_domain.ErrDeepLinkContextMissing
+	}
 	responseJWT, err := CreateReplyJWT(signer, deepLinkContext, session, items)
 	if err != nil {
 		http.Error(w, "failed to generate JWT", http.StatusInternalServerError)
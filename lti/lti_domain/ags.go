@@ -0,0 +1,85 @@
+package lti_domain
+
+// https://www.imsglobal.org/spec/lti-ags/v2p0
+const (
+	AGSScope_LineItem         = "https://purl.imsglobal.org/spec/lti-ags/scope/lineitem"
+	AGSScope_LineItemReadOnly = "https://purl.imsglobal.org/spec/lti-ags/scope/lineitem.readonly"
+	AGSScope_ResultReadOnly   = "https://purl.imsglobal.org/spec/lti-ags/scope/result.readonly"
+	AGSScope_Score            = "https://purl.imsglobal.org/spec/lti-ags/scope/score"
+)
+
+const (
+	AGSMediaType_LineItem          = "application/vnd.ims.lis.v2.lineitem+json"
+	AGSMediaType_LineItemContainer = "application/vnd.ims.lis.v2.lineitemcontainer+json"
+	AGSMediaType_Score             = "application/vnd.ims.lis.v1.score+json"
+)
+
+// LTIJWT_AGS captures the AGS endpoint claim sent by the platform at launch.
+type LTIJWT_AGS struct {
+	LineItems string   `json:"ls,omitempty"` // lineitems container URL for the context
+	LineItem  string   `json:"l,omitempty"`  // line item bound to the resource link, if any
+	Scopes    []string `json:"s,omitempty"`
+}
+
+// HasScope reports whether the platform granted the given AGS scope.
+func (a *LTIJWT_AGS) HasScope(scope string) bool {
+	if a == nil {
+		return false
+	}
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type LineItem struct {
+	ID             string  `json:"id,omitempty"`
+	ScoreMaximum   float64 `json:"scoreMaximum"`
+	Label          string  `json:"label"`
+	ResourceID     string  `json:"resourceId,omitempty"`
+	ResourceLinkID string  `json:"resourceLinkId,omitempty"`
+	Tag            string  `json:"tag,omitempty"`
+	StartDateTime  string  `json:"startDateTime,omitempty"`
+	EndDateTime    string  `json:"endDateTime,omitempty"`
+	GradesReleased *bool   `json:"gradesReleased,omitempty"`
+}
+
+// LineItemFilter narrows a line item listing. Empty fields are ignored.
+type LineItemFilter struct {
+	ResourceLinkID string
+	ResourceID     string
+	Tag            string
+	Limit          int
+}
+
+type ActivityProgress string
+
+const (
+	ActivityProgress_Initialized ActivityProgress = "Initialized"
+	ActivityProgress_Started     ActivityProgress = "Started"
+	ActivityProgress_InProgress  ActivityProgress = "InProgress"
+	ActivityProgress_Submitted   ActivityProgress = "Submitted"
+	ActivityProgress_Completed   ActivityProgress = "Completed"
+)
+
+type GradingProgress string
+
+const (
+	GradingProgress_FullyGraded   GradingProgress = "FullyGraded"
+	GradingProgress_Pending       GradingProgress = "Pending"
+	GradingProgress_PendingManual GradingProgress = "PendingManual"
+	GradingProgress_Failed        GradingProgress = "Failed"
+	GradingProgress_NotReady      GradingProgress = "NotReady"
+)
+
+type Score struct {
+	UserID           string           `json:"userId"`
+	ScoreGiven       *float64         `json:"scoreGiven,omitempty"`
+	ScoreMaximum     *float64         `json:"scoreMaximum,omitempty"`
+	Comment          string           `json:"comment,omitempty"`
+	Timestamp        string           `json:"timestamp"` // RFC 3339 with sub-second precision
+	ActivityProgress ActivityProgress `json:"activityProgress"`
+	GradingProgress  GradingProgress  `json:"gradingProgress"`
+}
@@ -0,0 +1,90 @@
+package lti_domain
+
+import (
+	"context"
+	"net/http"
+)
+
+const ContextKey_CookiePolicy string = "lti_cookie_policy"
+
+const hostCookiePrefix = "__Host-"
+
+// CookiePolicy controls the name and attributes of every cookie the framework
+// sets. The zero value matches the historical behaviour: Secure, SameSite=None
+// and no name prefix.
+type CookiePolicy struct {
+	// NamePrefix is prepended to every cookie name, so two tools served from
+	// the same host don't overwrite each other's sessions.
+	NamePrefix string
+
+	// Domain is copied onto each cookie. It is ignored when HostPrefix is set.
+	Domain string
+
+	// Insecure drops the Secure attribute. Only use it for plain HTTP local
+	// development.
+	Insecure bool
+
+	// SameSite defaults to http.SameSiteNoneMode, which LMS iframes require.
+	SameSite http.SameSite
+
+	// HostPrefix names cookies with the __Host- prefix. Browsers only accept
+	// those with Secure, Path=/ and no Domain, so the policy enforces all three.
+	HostPrefix bool
+
+	// Partitioned sets the CHIPS attribute so third-party iframe cookies are
+	// kept when the browser blocks unpartitioned ones.
+	Partitioned bool
+}
+
+// Name returns the cookie name used for base, e.g. ContextKey_Session.
+func (p CookiePolicy) Name(base string) string {
+	name := p.NamePrefix + base
+	if p.HostPrefix {
+		name = hostCookiePrefix + name
+	}
+	return name
+}
+
+// Cookie builds the cookie for base scoped to path.
+func (p CookiePolicy) Cookie(base, value, path string) *http.Cookie {
+	sameSite := p.SameSite
+	if sameSite == 0 {
+		sameSite = http.SameSiteNoneMode
+	}
+
+	cookie := &http.Cookie{
+		Name:        p.Name(base),
+		Value:       value,
+		Path:        path,
+		Domain:      p.Domain,
+		HttpOnly:    true,
+		Secure:      !p.Insecure,
+		SameSite:    sameSite,
+		Partitioned: p.Partitioned,
+	}
+
+	if p.HostPrefix {
+		cookie.Path = "/"
+		cookie.Domain = ""
+		cookie.Secure = true
+	}
+
+	return cookie
+}
+
+// Read returns the request cookie for base.
+func (p CookiePolicy) Read(r *http.Request, base string) (*http.Cookie, error) {
+	return r.Cookie(p.Name(base))
+}
+
+// ContextWithCookiePolicy stores the cookie policy into the request context.
+func ContextWithCookiePolicy(ctx context.Context, policy CookiePolicy) context.Context {
+	return context.WithValue(ctx, ContextKey_CookiePolicy, policy)
+}
+
+// CookiePolicyFromContext retrieves the cookie policy from context, falling
+// back to the zero value when none was set.
+func CookiePolicyFromContext(ctx context.Context) CookiePolicy {
+	val, _ := ctx.Value(ContextKey_CookiePolicy).(CookiePolicy)
+	return val
+}
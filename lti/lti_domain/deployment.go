@@ -26,6 +26,36 @@ type Deployment interface {
 	GetLTIDeploymentID() string
 }
 
+// DisableableDeployment is implemented by deployments that can be switched off
+// without being removed. Launches for a disabled deployment are rejected.
+type DisableableDeployment interface {
+	IsDisabled() bool
+}
+
+// IsDeploymentDisabled reports whether dep opts into DisableableDeployment and
+// is currently disabled.
+func IsDeploymentDisabled(dep Deployment) bool {
+	d, ok := dep.(DisableableDeployment)
+	return ok && d.IsDisabled()
+}
+
+// DeploymentFilter narrows ListDeployments. Zero-valued fields match everything.
+type DeploymentFilter struct {
+	TenantID TenantID
+	Issuer   string
+}
+
+// Matches reports whether dep satisfies every field set on the filter.
+func (f DeploymentFilter) Matches(dep Deployment) bool {
+	if f.TenantID != nil && TenantIDString(f.TenantID) != TenantIDString(dep.GetTenantID()) {
+		return false
+	}
+	if f.Issuer != "" && f.Issuer != dep.GetLTIIssuer() {
+		return false
+	}
+	return true
+}
+
 var _ Deployment = (*BaseLTIDeployment)(nil)
 var _ Deployment = BaseLTIDeployment{}
 
@@ -38,6 +68,11 @@ type BaseLTIDeployment struct {
 	AuthEndpoint  string
 	TokenEndpoint string
 	DeploymentID  string
+	Disabled      bool
+}
+
+func (d BaseLTIDeployment) IsDisabled() bool {
+	return d.Disabled
 }
 
 func (d BaseLTIDeployment) GetDeploymentID() string {
@@ -8,5 +8,24 @@ var (
 	ErrExchangeTokenNotFound         = errors.New("exchange token not found")
 	ErrExchangeTokenAlreadyExchanged = errors.New("exchange token already exchanged")
 	ErrExchangeRedemptionExpired     = errors.New("exchange redemption expired")
+	ErrNonceReplayed                 = errors.New("nonce already used")
 	ErrDeploymentNotFound            = errors.New("deployment not found")
+	ErrDeploymentExists              = errors.New("deployment already exists")
+	ErrDeploymentDisabled            = errors.New("deployment disabled")
+	ErrAGSNotAvailable               = errors.New("ags endpoint not available for this launch")
+	ErrAGSScopeNotGranted            = errors.New("ags scope not granted by platform")
+	ErrAGSForeignURL                 = errors.New("ags url is not on the platform's origin")
+	ErrServiceTokenRequest           = errors.New("service token request failed")
+	ErrNRPSNotAvailable              = errors.New("nrps endpoint not available for this launch")
+	ErrNRPSForeignURL                = errors.New("nrps url is not on the platform's origin")
+	ErrDeepLinkContextMissing        = errors.New("deep link context missing from request")
+	ErrRegistrationFailed            = errors.New("dynamic registration failed")
+	ErrInvalidIssuer                 = errors.New("id_token issuer does not match the deployment")
+	ErrInvalidAudience               = errors.New("id_token audience does not contain the client id")
+	ErrInvalidAuthorizedParty        = errors.New("id_token azp does not match the client id")
+	ErrDeploymentIDMismatch          = errors.New("id_token deployment_id does not match the login")
+	ErrUnsupportedLTIVersion         = errors.New("id_token lti version is not 1.3.0")
+	ErrSessionLifetimeExceeded       = errors.New("session reached its maximum lifetime")
+	ErrSessionRevoked                = errors.New("session revoked")
+	ErrSessionClaimsNotFound         = errors.New("session claims not found")
 )
@@ -7,23 +7,45 @@ import (
 // LTIJWT represents your internal app-issued JWT after an LTI launch.
 // It captures key contextual info for downstream authorization and telemetry.
 type LTIJWT struct {
-	TenantID               string              `json:"t"`
-	Deployment             string              `json:"d"`
-	ClientID               string              `json:"i"`
-	Roles                  []Role              `json:"r"`
-	UserInfo               LTIJWT_UserInfo     `json:"u"`
-	CourseInfo             LTIJWT_CourseInfo   `json:"c"`
-	LaunchType             LTIService          `json:"s"`
-	LinkedResourceID       string              `json:"lr"`
-	Platform               LTIJWT_ToolPlatform `json:"p"`
-	Custom                 map[string]any      `json:"cu"`
-	Impostering            bool                `json:"im"`
-	ImposteringSrc         string              `json:"ims,omitempty"`
-	ImposterLaunchRedirect string              `json:"ilr,omitempty"`
-	SessionID              string              `json:"si,omitempty"`
+	TenantID               string               `json:"t"`
+	Deployment             string               `json:"d"`
+	ClientID               string               `json:"i"`
+	Roles                  []Role               `json:"r"`
+	UserInfo               LTIJWT_UserInfo      `json:"u"`
+	CourseInfo             LTIJWT_CourseInfo    `json:"c"`
+	LaunchType             LTIService           `json:"s"`
+	LTIVersion             string               `json:"v,omitempty"`
+	LinkedResourceID       string               `json:"lr"`
+	LinkedResourceTitle    string               `json:"lrt,omitempty"`
+	LinkedResourceDesc     string               `json:"lrd,omitempty"`
+	Platform               LTIJWT_ToolPlatform  `json:"p"`
+	Custom                 map[string]any       `json:"cu"`
+	Impostering            bool                 `json:"im"`
+	ImposteringSrc         string               `json:"ims,omitempty"`
+	ImposterLaunchRedirect string               `json:"ilr,omitempty"`
+	SessionID              string               `json:"si,omitempty"`
+	SessionStart           *jwt.NumericDate     `json:"ss,omitempty"`
+	AGS                    *LTIJWT_AGS          `json:"ag,omitempty"`
+	NRPS                   *LTIJWT_NRPS         `json:"nr,omitempty"`
+	ForUser                *LTIJWT_ForUser      `json:"fu,omitempty"`
+	LaunchPresentation     *LTIJWT_Presentation `json:"lp,omitempty"`
+	LIS                    *LTIJWT_LIS          `json:"li,omitempty"`
+	RoleScopeMentor        []string             `json:"rm,omitempty"` // user IDs the user mentors
+	Extra                  map[string]any       `json:"x,omitempty"`  // set by launch hooks
+	// ClaimsRef names the SessionClaims kept server-side for this session
+	// when they would have made the token too large.
+	ClaimsRef string `json:"cr,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// SetExtra sets a tool-defined claim, typically from a launch hook.
+func (j *LTIJWT) SetExtra(key string, value any) {
+	if j.Extra == nil {
+		j.Extra = map[string]any{}
+	}
+	j.Extra[key] = value
+}
+
 type LTIJWT_ToolPlatform struct {
 	GUID              string `json:"g"`
 	Name              string `json:"n"`
@@ -33,9 +55,26 @@ type LTIJWT_ToolPlatform struct {
 }
 
 type LTIJWT_CourseInfo struct {
-	CourseID    string `json:"c"`
-	CourseLabel string `json:"l"`
-	CourseTitle string `json:"n"`
+	CourseID    string   `json:"c"`
+	CourseLabel string   `json:"l"`
+	CourseTitle string   `json:"n"`
+	CourseType  []string `json:"t,omitempty"` // context type URIs, e.g. CourseSection
+}
+
+// LTIJWT_Presentation is the launch_presentation claim: how the platform
+// shows the tool and where to send the user back to.
+type LTIJWT_Presentation struct {
+	DocumentTarget string `json:"dt,omitempty"` // iframe, window or frame
+	ReturnURL      string `json:"ru,omitempty"`
+	Locale         string `json:"l,omitempty"`
+	Width          int    `json:"w,omitempty"`
+	Height         int    `json:"h,omitempty"`
+}
+
+// LTIJWT_LIS holds the SIS identifiers from the lis claim.
+type LTIJWT_LIS struct {
+	PersonSourcedID        string `json:"ps,omitempty"`
+	CourseSectionSourcedID string `json:"cs,omitempty"`
 }
 
 type LTIJWT_UserInfo struct {
@@ -49,6 +88,18 @@ type LTIJWT_UserInfo struct {
 	Locale     string `json:"l,omitempty"`
 }
 
+// LTIJWT_ForUser is the user a launch is about rather than the one making it,
+// such as the student whose submission an instructor reviews.
+type LTIJWT_ForUser struct {
+	UserID          string `json:"u"`
+	PersonSourcedID string `json:"ps,omitempty"`
+	Name            string `json:"n,omitempty"`
+	GivenName       string `json:"g,omitempty"`
+	FamilyName      string `json:"f,omitempty"`
+	Email           string `json:"e,omitempty"`
+	Roles           []Role `json:"r,omitempty"`
+}
+
 type DeepLinkingTarget string
 
 const (
@@ -75,5 +126,5 @@ type DeepLinkContext struct {
 	Targets          []DeepLinkingTarget `json:"t"` // allowed presentation targets (iframe, window)
 	AutoCreate       bool                `json:"c"` // whether LMS auto-adds items
 	AcceptMediaTypes string              `json:"m"`
-	AttachedKID      string              `json:"k"` // All must be attached to a valid Session JWT
+	AttachedKID      string              `json:"k"` // SessionID of the session it belongs to; survives refresh
 }
@@ -0,0 +1,16 @@
+package lti_domain
+
+// LaunchDeniedError is returned by a launch hook to veto a launch. Message is
+// shown to the user on the auth error page.
+type LaunchDeniedError struct {
+	Message string
+}
+
+func (e *LaunchDeniedError) Error() string {
+	return "launch denied: " + e.Message
+}
+
+// DenyLaunch vetoes a launch from a launch hook, showing msg to the user.
+func DenyLaunch(msg string) error {
+	return &LaunchDeniedError{Message: msg}
+}
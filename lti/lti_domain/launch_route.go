@@ -0,0 +1,60 @@
+package lti_domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// LaunchRoute sends launches that match it to To, a path under the app routes
+// such as "/quiz/". Every matcher that is set must match; a route with none
+// set matches every launch.
+type LaunchRoute struct {
+	// TargetPath matches the path of the target_link_uri the platform
+	// launched, e.g. "/lti/1.3/launch/quiz". It matches exactly, or as a
+	// prefix when it ends in "/". The server serves the launch endpoint and
+	// every path under it, so target links should point below the launch URL.
+	TargetPath string
+
+	// ResourceLinkID matches the id of the resource link that was clicked.
+	ResourceLinkID string
+
+	// CustomKey and CustomValue match a custom parameter. An empty
+	// CustomValue matches any value of CustomKey.
+	CustomKey   string
+	CustomValue string
+
+	To string
+}
+
+// Matches reports whether launch should be sent to r.To.
+func (r LaunchRoute) Matches(launch *Launch) bool {
+	if r.TargetPath != "" {
+		target, err := url.Parse(launch.TargetLinkURI)
+		if err != nil || launch.TargetLinkURI == "" {
+			return false
+		}
+		if target.Path != r.TargetPath && !(strings.HasSuffix(r.TargetPath, "/") && strings.HasPrefix(target.Path, r.TargetPath)) {
+			return false
+		}
+	}
+
+	if r.ResourceLinkID != "" && (launch.Session == nil || launch.Session.LinkedResourceID != r.ResourceLinkID) {
+		return false
+	}
+
+	if r.CustomKey != "" {
+		if launch.Session == nil {
+			return false
+		}
+		v, ok := launch.Session.Custom[r.CustomKey]
+		if !ok {
+			return false
+		}
+		if r.CustomValue != "" && fmt.Sprint(v) != r.CustomValue {
+			return false
+		}
+	}
+
+	return true
+}
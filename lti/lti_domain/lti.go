@@ -1,8 +1,25 @@
 package lti_domain
 
+import "github.com/golang-jwt/jwt/v5"
+
 type LTIService string
 
 const (
 	LTIService_ResourceLink LTIService = "LtiResourceLinkRequest"
 	LTIService_DeepLink     LTIService = "LtiDeepLinkingRequest"
+	// LTIService_SubmissionReview opens a student's submission for an AGS
+	// line item; the student is in LTIJWT.ForUser.
+	LTIService_SubmissionReview LTIService = "LtiSubmissionReviewRequest"
 )
+
+// Launch is a validated launch handed to the message handler registered for
+// its message type.
+type Launch struct {
+	MessageType LTIService
+	// Session is the session built from the id_token, after launch hooks ran.
+	Session *LTIJWT
+	// Claims are the platform's id_token claims.
+	Claims jwt.MapClaims
+	// TargetLinkURI is the target_link_uri validated during the OIDC login.
+	TargetLinkURI string
+}
@@ -10,6 +10,16 @@ const ContextKey_CookieConfirmation string = "lti_supported"
 
 const ContextKey_SessionID string = "lti_session_id"
 
+// ContextKey_SessionRefresh names the companion cookie that carries the
+// session to the refresh endpoint.
+const ContextKey_SessionRefresh string = "lti_session_refresh"
+
+const ContextKey_StateBinding string = "lti_state"
+
+// ContextKey_RawSession holds the signed session token a request was
+// verified with.
+const ContextKey_RawSession string = "rawJWT"
+
 // ContextWithLTI stores LTIJWT into the request context.
 func ContextWithLTI(ctx context.Context, claims *LTIJWT) context.Context {
 	return context.WithValue(ctx, ContextKey_Session, claims)
@@ -20,3 +30,10 @@ func LTIFromContext(ctx context.Context) (*LTIJWT, bool) {
 	val, ok := ctx.Value(ContextKey_Session).(*LTIJWT)
 	return val, ok
 }
+
+// RawSessionFromContext returns the signed session token the request was
+// verified with, if present.
+func RawSessionFromContext(ctx context.Context) (string, bool) {
+	val, ok := ctx.Value(ContextKey_RawSession).(string)
+	return val, ok
+}
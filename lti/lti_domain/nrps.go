@@ -0,0 +1,54 @@
+package lti_domain
+
+// https://www.imsglobal.org/spec/lti-nrps/v2p0
+const NRPSScope_ContextMembershipReadOnly = "https://purl.imsglobal.org/spec/lti-nrps/scope/contextmembership.readonly"
+
+const NRPSMediaType_MembershipContainer = "application/vnd.ims.lti-nrps.v2.membershipcontainer+json"
+
+// LTIJWT_NRPS captures the names and role service claim sent by the platform at launch.
+type LTIJWT_NRPS struct {
+	ContextMembershipsURL string   `json:"u"`
+	ServiceVersions       []string `json:"v,omitempty"`
+}
+
+type MembershipStatus string
+
+const (
+	MembershipStatus_Active   MembershipStatus = "Active"
+	MembershipStatus_Inactive MembershipStatus = "Inactive"
+	MembershipStatus_Deleted  MembershipStatus = "Deleted"
+)
+
+type Member struct {
+	UserID             string           `json:"user_id"`
+	Status             MembershipStatus `json:"status,omitempty"`
+	Name               string           `json:"name,omitempty"`
+	Picture            string           `json:"picture,omitempty"`
+	GivenName          string           `json:"given_name,omitempty"`
+	FamilyName         string           `json:"family_name,omitempty"`
+	MiddleName         string           `json:"middle_name,omitempty"`
+	Email              string           `json:"email,omitempty"`
+	LisPersonSourcedID string           `json:"lis_person_sourcedid,omitempty"`
+	Roles              []Role           `json:"-"`
+	RoleURIs           []string         `json:"roles"`
+}
+
+// Membership is a context roster, aggregated across every page the platform returned.
+type Membership struct {
+	ID           string
+	ContextID    string
+	ContextLabel string
+	ContextTitle string
+	Members      []Member
+
+	// DifferencesURL, when offered by the platform, returns only the changes
+	// since this roster was fetched. Pass it to GetMembershipDifferences.
+	DifferencesURL string
+}
+
+// MembershipFilter narrows a roster request. Empty fields are ignored.
+type MembershipFilter struct {
+	Role           string // full role URI, e.g. http://purl.imsglobal.org/vocab/lis/v2/membership#Learner
+	ResourceLinkID string // restrict to members with access to this resource link (rlid)
+	Limit          int    // page size hint
+}
@@ -0,0 +1,37 @@
+package lti_domain
+
+// StorageLogin is what an OIDC login keeps in the platform's frame under the
+// LTI Client Side postMessages spec.
+type StorageLogin struct {
+	// Target is the frame named by lti_storage_target.
+	Target string
+
+	// Origin is the platform origin messages are exchanged with, taken from
+	// the OIDC auth endpoint.
+	Origin string
+
+	State string
+	Nonce string
+
+	// Extra holds further keys to store alongside state and nonce.
+	Extra map[string]string
+}
+
+// StorageCheck is the outcome of reading a login back from platform storage.
+type StorageCheck uint8
+
+const (
+	// StorageCheckPending means a lookup page was written and the request is
+	// finished; the browser posts the launch again with the answers.
+	StorageCheckPending StorageCheck = iota
+
+	// StorageCheckVerified means the platform returned the state and nonce.
+	StorageCheckVerified
+
+	// StorageCheckUnavailable means the platform didn't answer, so the launch
+	// continues with the cookie flow.
+	StorageCheckUnavailable
+
+	// StorageCheckMismatch means the platform answered with other values.
+	StorageCheckMismatch
+)
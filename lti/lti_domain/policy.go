@@ -1,10 +1,94 @@
 package lti_domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
+// Policy tunes how strictly launches are validated. Zero fields fall back to
+// DefaultPolicy.
 type Policy struct {
+	// MaxClockSkew is the leeway applied to exp, nbf and iat when parsing the
+	// platform id_token.
 	MaxClockSkew time.Duration
-	StateTTL     time.Duration
-	NonceTTL     time.Duration
-	AllowedURIs  []string
+
+	// StateTTL bounds the time between the OIDC login and the launch.
+	StateTTL time.Duration
+
+	// NonceTTL bounds how long a launch nonce is remembered.
+	NonceTTL time.Duration
+
+	// SwapTokenTTL bounds the time between the launch and the code swap.
+	SwapTokenTTL time.Duration
+
+	// SessionTTL is how long each signed session token is valid. Sessions are
+	// extended by calling the refresh endpoint before it runs out.
+	SessionTTL time.Duration
+
+	// MaxSessionLifetime caps how long a session can be refreshed, counted
+	// from the launch. After that the user has to relaunch from the LMS.
+	MaxSessionLifetime time.Duration
+
+	// MaxSessionClaimsSize is the largest encoded session, in bytes, kept
+	// entirely in its token. Larger sessions keep their SessionClaims in the
+	// ephemeral store instead. Zero keeps every claim in the token.
+	MaxSessionClaimsSize int
+
+	// AllowedURIs restricts the OIDC target_link_uri. Entries match exactly,
+	// or as a prefix when they end in "/". Empty allows any URI under the
+	// tool's base URL.
+	AllowedURIs []string
+}
+
+// DefaultPolicy returns the values used when no policy is configured.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxClockSkew: 0,
+		StateTTL:     5 * time.Minute,
+		NonceTTL:     10 * time.Minute,
+		SwapTokenTTL: 30 * time.Second,
+
+		SessionTTL:         time.Hour,
+		MaxSessionLifetime: 12 * time.Hour,
+	}
+}
+
+// WithDefaults fills unset durations from DefaultPolicy.
+func (p Policy) WithDefaults() Policy {
+	def := DefaultPolicy()
+	if p.MaxClockSkew < 0 {
+		p.MaxClockSkew = def.MaxClockSkew
+	}
+	if p.StateTTL <= 0 {
+		p.StateTTL = def.StateTTL
+	}
+	if p.NonceTTL <= 0 {
+		p.NonceTTL = def.NonceTTL
+	}
+	if p.SwapTokenTTL <= 0 {
+		p.SwapTokenTTL = def.SwapTokenTTL
+	}
+	if p.SessionTTL <= 0 {
+		p.SessionTTL = def.SessionTTL
+	}
+	if p.MaxSessionLifetime <= 0 {
+		p.MaxSessionLifetime = def.MaxSessionLifetime
+	}
+	return p
+}
+
+// AllowsURI reports whether uri is permitted by AllowedURIs.
+func (p Policy) AllowsURI(uri string) bool {
+	if len(p.AllowedURIs) == 0 {
+		return true
+	}
+	for _, allowed := range p.AllowedURIs {
+		if uri == allowed {
+			return true
+		}
+		if strings.HasSuffix(allowed, "/") && strings.HasPrefix(uri, allowed) {
+			return true
+		}
+	}
+	return false
 }
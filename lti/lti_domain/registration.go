@@ -0,0 +1,35 @@
+package lti_domain
+
+// PlatformConfiguration is the subset of a platform's OpenID configuration
+// used by LTI Dynamic Registration.
+// https://www.imsglobal.org/spec/lti-dr/v1p0#platform-configuration
+type PlatformConfiguration struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	RegistrationEndpoint  string   `json:"registration_endpoint"`
+	ScopesSupported       []string `json:"scopes_supported,omitempty"`
+
+	LTIPlatform LTIPlatformConfiguration `json:"https://purl.imsglobal.org/spec/lti-platform-configuration"`
+}
+
+type LTIPlatformConfiguration struct {
+	ProductFamilyCode string                     `json:"product_family_code"`
+	Version           string                     `json:"version,omitempty"`
+	MessagesSupported []LTIPlatformMessageConfig `json:"messages_supported,omitempty"`
+}
+
+type LTIPlatformMessageConfig struct {
+	Type string `json:"type"`
+}
+
+// ToolRegistration describes the tool endpoints advertised to a platform
+// during Dynamic Registration. The server builds it from the launcher.
+type ToolRegistration struct {
+	BaseURL      string
+	OIDCLoginURL string
+	LaunchURL    string
+	JWKSURL      string
+	Messages     []LTIService
+}
@@ -0,0 +1,131 @@
+package lti_domain
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+const ContextKey_Routes string = "lti_routes"
+
+const ContextKey_LTIVersion string = "lti_version"
+
+// DefaultLTIVersion is the version segment used when none was configured.
+const DefaultLTIVersion = "1.3"
+
+// Routes controls where the LTI endpoints are mounted. AppPath and AuthPath
+// are relative to Prefix, so the defaults serve protected routes under
+// /lti/app and the fallback pages under /lti/auth. An empty Prefix mounts
+// everything at the root.
+type Routes struct {
+	Prefix   string
+	AppPath  string
+	AuthPath string
+}
+
+// DefaultRoutes returns the /lti, /app and /auth layout.
+func DefaultRoutes() Routes {
+	return Routes{
+		Prefix:   "/lti",
+		AppPath:  "/app",
+		AuthPath: "/auth",
+	}
+}
+
+// Normalize cleans each path to a leading slash with no trailing slash and
+// fills an empty AppPath or AuthPath with its default.
+func (r Routes) Normalize() Routes {
+	def := DefaultRoutes()
+	out := Routes{
+		Prefix:   cleanRoutePath(r.Prefix),
+		AppPath:  cleanRoutePath(r.AppPath),
+		AuthPath: cleanRoutePath(r.AuthPath),
+	}
+	if out.AppPath == "" {
+		out.AppPath = def.AppPath
+	}
+	if out.AuthPath == "" {
+		out.AuthPath = def.AuthPath
+	}
+	return out
+}
+
+func cleanRoutePath(p string) string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return ""
+	}
+	return "/" + p
+}
+
+// Path joins elem onto the mount prefix.
+func (r Routes) Path(elem ...string) string {
+	out := r.Prefix
+	for _, e := range elem {
+		if e = strings.Trim(e, "/"); e != "" {
+			out += "/" + e
+		}
+	}
+	return out
+}
+
+// App is the root of the protected routes, without a trailing slash.
+func (r Routes) App() string {
+	return r.Prefix + r.AppPath
+}
+
+// Auth is the root of the fallback authorizer pages, without a trailing slash.
+func (r Routes) Auth() string {
+	return r.Prefix + r.AuthPath
+}
+
+// Refresh is the session refresh endpoint.
+func (r Routes) Refresh() string {
+	return r.Auth() + "/refresh"
+}
+
+// Logout revokes the current session and clears its cookies.
+func (r Routes) Logout() string {
+	return r.Auth() + "/logout"
+}
+
+// Versioned is an endpoint for a specific LTI version, e.g. /lti/1.3/launch.
+func (r Routes) Versioned(version, endpoint string) string {
+	return r.Path(version, endpoint)
+}
+
+// ErrorURL is the auth error page carrying msg in its err parameter.
+func (r Routes) ErrorURL(msg string) string {
+	params := url.Values{}
+	params.Add("err", msg)
+	return r.Auth() + "/error?" + params.Encode()
+}
+
+// ContextWithRoutes stores the route configuration into the request context.
+func ContextWithRoutes(ctx context.Context, routes Routes) context.Context {
+	return context.WithValue(ctx, ContextKey_Routes, routes)
+}
+
+// RoutesFromContext retrieves the route configuration from context, falling
+// back to DefaultRoutes when none was set.
+func RoutesFromContext(ctx context.Context) Routes {
+	if val, ok := ctx.Value(ContextKey_Routes).(Routes); ok {
+		return val
+	}
+	return DefaultRoutes()
+}
+
+// ContextWithLTIVersion stores the launcher's LTI version, the segment its
+// versioned endpoints are mounted under, into the request context.
+func ContextWithLTIVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, ContextKey_LTIVersion, version)
+}
+
+// LTIVersionFromContext retrieves the LTI version from context, falling back
+// to DefaultLTIVersion when none was set.
+func LTIVersionFromContext(ctx context.Context) string {
+	if val, ok := ctx.Value(ContextKey_LTIVersion).(string); ok && val != "" {
+		return val
+	}
+	return DefaultLTIVersion
+}
@@ -0,0 +1,34 @@
+package lti_domain
+
+// SessionClaims are the parts of a session that can grow without bound. When
+// Policy.MaxSessionClaimsSize is set and a session outgrows it, they are kept
+// in the ephemeral store under LTIJWT.ClaimsRef rather than in the token.
+type SessionClaims struct {
+	Custom             map[string]any       `json:"cu,omitempty"`
+	Extra              map[string]any       `json:"x,omitempty"`
+	LaunchPresentation *LTIJWT_Presentation `json:"lp,omitempty"`
+	RoleScopeMentor    []string             `json:"rm,omitempty"`
+}
+
+// SplitSessionClaims removes the SessionClaims from j and returns them.
+func (j *LTIJWT) SplitSessionClaims() SessionClaims {
+	claims := SessionClaims{
+		Custom:             j.Custom,
+		Extra:              j.Extra,
+		LaunchPresentation: j.LaunchPresentation,
+		RoleScopeMentor:    j.RoleScopeMentor,
+	}
+	j.Custom = nil
+	j.Extra = nil
+	j.LaunchPresentation = nil
+	j.RoleScopeMentor = nil
+	return claims
+}
+
+// MergeSessionClaims puts claims split off by SplitSessionClaims back into j.
+func (j *LTIJWT) MergeSessionClaims(claims SessionClaims) {
+	j.Custom = claims.Custom
+	j.Extra = claims.Extra
+	j.LaunchPresentation = claims.LaunchPresentation
+	j.RoleScopeMentor = claims.RoleScopeMentor
+}
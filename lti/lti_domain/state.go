@@ -11,6 +11,18 @@ type State struct {
 	Nonce        string
 	TenantID     TenantID
 	CreatedAt    time.Time
+
+	// BindingHash is set when the state is bound to the browser that started
+	// the login. It is the hash of the value held in the state cookie.
+	BindingHash string `json:",omitempty"`
+
+	// StorageTarget is the platform frame named by lti_storage_target, used
+	// for postMessage storage when cookies are blocked.
+	StorageTarget string `json:",omitempty"`
+
+	// TargetLinkURI is the target_link_uri of the login, checked against the
+	// tool's routes and the policy's AllowedURIs.
+	TargetLinkURI string `json:",omitempty"`
 }
 
 type SwapToken struct {
@@ -18,6 +30,14 @@ type SwapToken struct {
 	RequestorUA string    `json:"ua"`
 	Claims      LTIJWT    `json:"jwt"`
 	StartAt     time.Time `json:"sa"`
+
+	// StorageVerified is set when the launch was bound to the browser through
+	// platform storage, so the swap skips the confirmation cookie.
+	StorageVerified bool `json:"sv,omitempty"`
+
+	// StorageAccess is set when the swap is retried after the browser
+	// granted cookie access through the Storage Access API.
+	StorageAccess bool `json:"sac,omitempty"`
 }
 
 type ExchangeToken struct {
@@ -8,6 +8,8 @@ const (
 	LaunchMethodUnknown LaunchMethod = iota
 	LaunchMethodDirect
 	LaunchMethodPKCE
+	LaunchMethodPlatformStorage
+	LaunchMethodStorageAccess
 )
 
 func (m LaunchMethod) String() string {
@@ -16,6 +18,10 @@ func (m LaunchMethod) String() string {
 		return "DirectLaunch"
 	case LaunchMethodPKCE:
 		return "PKCELaunch"
+	case LaunchMethodPlatformStorage:
+		return "PlatformStorageLaunch"
+	case LaunchMethodStorageAccess:
+		return "StorageAccessLaunch"
 	default:
 		return "Unknown"
 	}
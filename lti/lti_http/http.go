@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	internal "github.com/vizdos-enterprises/go-lti/internal/adapters/server"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
 )
 
@@ -23,6 +24,10 @@ func (h HTTPServer) GetVerifier() lti_ports.Verifier {
 	return h.inner.GetVerifier()
 }
 
+func (h HTTPServer) GetRoutes() lti_domain.Routes {
+	return h.inner.GetRoutes()
+}
+
 // NewServer constructs a new LTI Server using the provided options.
 // It panics if required fields (launcher, verifier) are missing.
 func NewServer(opts ...ServerOption) *HTTPServer {
@@ -2,6 +2,7 @@ package lti_http
 
 import (
 	internal "github.com/vizdos-enterprises/go-lti/internal/adapters/server"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
 )
 
@@ -30,3 +31,44 @@ func WithImpostering(im lti_ports.Impostering) ServerOption {
 		return internal.WithImpostering(im)
 	}}
 }
+
+// WithRoutes mounts the LTI endpoints under a custom prefix, app path and auth
+// path. Defaults to lti_domain.DefaultRoutes().
+func WithRoutes(routes lti_domain.Routes) ServerOption {
+	return ServerOption{toInternal: func() internal.ServerOption {
+		return internal.WithRoutes(routes)
+	}}
+}
+
+// WithCookiePolicy sets the cookie name prefix, Domain, Secure, SameSite,
+// __Host- prefixing and Partitioned attributes used by every adapter behind
+// this server.
+func WithCookiePolicy(policy lti_domain.CookiePolicy) ServerOption {
+	return ServerOption{toInternal: func() internal.ServerOption {
+		return internal.WithCookiePolicy(policy)
+	}}
+}
+
+// WithDynamicRegistration mounts the LTI Dynamic Registration route at {prefix}/register.
+func WithDynamicRegistration(reg lti_ports.DynamicRegistration) ServerOption {
+	return ServerOption{toInternal: func() internal.ServerOption {
+		return internal.WithDynamicRegistration(reg)
+	}}
+}
+
+// WithSessionRevoker rejects sessions revoked through revoker on every
+// protected route, and makes the logout route revoke the session it ends.
+func WithSessionRevoker(revoker lti_ports.SessionRevoker) ServerOption {
+	return ServerOption{toInternal: func() internal.ServerOption {
+		return internal.WithSessionRevoker(revoker)
+	}}
+}
+
+// WithSessionClaimStore restores the claims kept server-side for sessions
+// larger than Policy.MaxSessionClaimsSize. Pass the launcher's ephemeral
+// store.
+func WithSessionClaimStore(store lti_ports.EphemeralStore) ServerOption {
+	return ServerOption{toInternal: func() internal.ServerOption {
+		return internal.WithSessionClaimStore(store)
+	}}
+}
@@ -1,8 +1,12 @@
 package lti_http
 
 import (
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/deployment_admin"
 	"github.com/vizdos-enterprises/go-lti/internal/adapters/helper_routes"
 	internal "github.com/vizdos-enterprises/go-lti/internal/adapters/server"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/server/middleware"
 
 	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
@@ -25,3 +29,55 @@ func RegisterSessionInfoJS(distinctIdGenerator func(*lti_domain.LTIJWT) string)
 		AllowImpostering:       true,
 	}
 }
+
+// NewDeploymentAdmin returns a JSON handler for listing, adding, updating and
+// removing deployments. Every request must pass authorize. Paths are relative
+// (/deployments, /deployments/{clientID}/{deploymentID}); mount it under your
+// own prefix with http.StripPrefix.
+func NewDeploymentAdmin(registry lti_ports.Registry, authorize lti_ports.AdminAuthorizer, logger lti_ports.Logger) http.Handler {
+	return deployment_admin.NewDeploymentAdminHTTP(registry, authorize, logger)
+}
+
+// VerifyLTIBearer is a ProtectedRoute.Verifier for API routes. It accepts the
+// session from an "Authorization: Bearer <jwt>" header as well as the session
+// cookie, and answers failures with JSON 401/403 responses instead of
+// redirecting to the auth error page.
+func VerifyLTIBearer(verifier lti_ports.Verifier, expectedAudience []string, allowImpostering bool, next http.Handler) http.Handler {
+	return middleware.VerifyLTIBearer(verifier, expectedAudience, allowImpostering, next)
+}
+
+// TokenHandoffMode selects how RegisterTokenHandoff passes the session token.
+type TokenHandoffMode = helper_routes.TokenHandoffMode
+
+const (
+	// TokenHandoffFragment redirects to the target with #lti_token=<jwt>.
+	TokenHandoffFragment = helper_routes.TokenHandoffFragment
+	// TokenHandoffBootstrap stores the token in sessionStorage["lti_token"]
+	// and then opens the target, which must share the tool's origin.
+	TokenHandoffBootstrap = helper_routes.TokenHandoffBootstrap
+)
+
+// RegisterTokenHandoff serves path under the app routes and hands the cookie
+// session to a single page app at target, which can then call APIs guarded by
+// VerifyLTIBearer. Register it at "/" to hand over on every launch.
+func RegisterTokenHandoff(path, target string, mode TokenHandoffMode) lti_ports.ProtectedRoute {
+	return lti_ports.ProtectedRoute{
+		Path:             path,
+		Role:             []lti_domain.Role{},
+		Handler:          helper_routes.NewTokenHandoffHTTP(target, mode),
+		AllowImpostering: true,
+	}
+}
+
+// RegisterSessionRefreshJS serves a script that keeps the session alive by
+// calling the launcher's refresh endpoint before the session cookie expires.
+// It fires "lti:session-refreshed" on window after every refresh and
+// "lti:session-expired" once the session can no longer be extended.
+func RegisterSessionRefreshJS() lti_ports.ProtectedRoute {
+	return lti_ports.ProtectedRoute{
+		Path:             "/session-refresh.js",
+		Role:             []lti_domain.Role{},
+		Handler:          helper_routes.NewSessionRefreshHTTP(),
+		AllowImpostering: true,
+	}
+}
@@ -2,6 +2,7 @@ package lti_impostering
 
 import (
 	"github.com/vizdos-enterprises/go-lti/internal/adapters/impostering"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
 )
 
@@ -25,6 +26,19 @@ func WithSessionAudience(audience []string) lti_ports.ImposteringOption {
 	return impostering.WithSessionAudience(audience)
 }
 
+// WithSessionPolicy sets the session TTL and maximum lifetime for imposter
+// sessions. Only SessionTTL and MaxSessionLifetime are used.
+func WithSessionPolicy(policy lti_domain.Policy) lti_ports.ImposteringOption {
+	return impostering.WithSessionPolicy(policy)
+}
+
+// WithLaunchHooks runs hooks on every imposter launch before its session is
+// issued. Pass the same hooks as lti_launcher.WithLaunchHooks so imposter
+// sessions are provisioned like real ones; hooks get nil id_token claims.
+func WithLaunchHooks(hooks ...lti_ports.LaunchHook) lti_ports.ImposteringOption {
+	return impostering.WithLaunchHooks(hooks...)
+}
+
 func WithLogger(logger lti_ports.Logger) lti_ports.ImposteringOption {
 	return impostering.WithLogger(logger)
 }
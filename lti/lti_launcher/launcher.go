@@ -100,3 +100,80 @@ func WithTelemetry(telemetry lti_ports.TelemetryPort) LauncherOption {
 		return launcher1dot3.WithTelemetry(telemetry)
 	}}
 }
+
+// WithPolicy sets the state TTL, swap token TTL, id_token clock skew leeway
+// and allowed target_link_uri values.
+func WithPolicy(policy lti_domain.Policy) LauncherOption {
+	return LauncherOption{toInternal: func() launcher1dot3.LauncherOptions {
+		return launcher1dot3.WithPolicy(policy)
+	}}
+}
+
+// WithStateBinding ties each login to the browser that started it with a
+// state cookie, falling back to platform postMessage storage when the cookie
+// is blocked. Recommended by the LTI security framework against login CSRF.
+func WithStateBinding() LauncherOption {
+	return LauncherOption{toInternal: func() launcher1dot3.LauncherOptions {
+		return launcher1dot3.WithStateBinding()
+	}}
+}
+
+// WithPlatformStorage verifies launches through the platform's postMessage
+// storage (LTI Client Side postMessages) when the platform sends
+// lti_storage_target. Verified launches skip the cookie check; the rest fall
+// back to the PKCE flow or the configured fallback authorizer.
+func WithPlatformStorage() LauncherOption {
+	return LauncherOption{toInternal: func() launcher1dot3.LauncherOptions {
+		return launcher1dot3.WithPlatformStorage()
+	}}
+}
+
+// WithStorageAccess asks the browser for third-party cookie access with the
+// Storage Access API before falling back to the PKCE flow. Launches completed
+// this way are reported as StorageAccessLaunch.
+func WithStorageAccess() LauncherOption {
+	return LauncherOption{toInternal: func() launcher1dot3.LauncherOptions {
+		return launcher1dot3.WithStorageAccess()
+	}}
+}
+
+// WithLaunchHooks runs hooks, in order, on resource link, deep link and
+// imposter launches after the launch is validated and before the session is
+// issued. Hooks can enrich the session or veto the launch with
+// lti_domain.DenyLaunch.
+func WithLaunchHooks(hooks ...lti_ports.LaunchHook) LauncherOption {
+	return LauncherOption{toInternal: func() launcher1dot3.LauncherOptions {
+		return launcher1dot3.WithLaunchHooks(hooks...)
+	}}
+}
+
+// WithMessageHandler finishes launches of messageType with handler. Use it to
+// accept message types beyond resource links and deep linking, or to replace
+// the built-in handling of either. Launches of unregistered message types are
+// rejected.
+func WithMessageHandler(messageType lti_domain.LTIService, handler lti_ports.MessageHandler) LauncherOption {
+	return LauncherOption{toInternal: func() launcher1dot3.LauncherOptions {
+		return launcher1dot3.WithMessageHandler(messageType, handler)
+	}}
+}
+
+// WithSubmissionReview accepts LtiSubmissionReviewRequest launches, sent when
+// an instructor opens a student's submission from the gradebook, and lands
+// them on path under the app routes. Pair it with a ProtectedRoute whose
+// LaunchTypes is lti_domain.LTIService_SubmissionReview; the student is in
+// the session's ForUser.
+func WithSubmissionReview(path string) LauncherOption {
+	return LauncherOption{toInternal: func() launcher1dot3.LauncherOptions {
+		return launcher1dot3.WithSubmissionReview(path)
+	}}
+}
+
+// WithLaunchRoutes lands launches on different pages under the app routes,
+// chosen by the target_link_uri path, the resource link or a custom
+// parameter. The first matching route wins; the rest land on the app root.
+// The session cookie covers the whole app, whichever page a launch lands on.
+func WithLaunchRoutes(routes ...lti_domain.LaunchRoute) LauncherOption {
+	return LauncherOption{toInternal: func() launcher1dot3.LauncherOptions {
+		return launcher1dot3.WithLaunchRoutes(routes...)
+	}}
+}
@@ -0,0 +1,37 @@
+package lti_nrps
+
+import (
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/nrps"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// NewNRPS returns a Names and Role Provisioning Services client that pages
+// through context memberships and supports differential sync.
+func NewNRPS(opts ...lti_ports.NRPSOption) lti_ports.NRPS {
+	return nrps.NewNRPS(opts...)
+}
+
+// WithRegistry sets the registry used to resolve the session's deployment.
+func WithRegistry(registry lti_ports.Registry) lti_ports.NRPSOption {
+	return nrps.WithRegistry(registry)
+}
+
+// WithSigner sets the signer used to build a default token provider.
+func WithSigner(signer lti_ports.AsymetricSigner) lti_ports.NRPSOption {
+	return nrps.WithSigner(signer)
+}
+
+// WithTokenProvider sets the provider used to obtain NRPS access tokens.
+func WithTokenProvider(tokens lti_ports.ServiceTokenProvider) lti_ports.NRPSOption {
+	return nrps.WithTokenProvider(tokens)
+}
+
+func WithHTTPClient(client *http.Client) lti_ports.NRPSOption {
+	return nrps.WithHTTPClient(client)
+}
+
+func WithLogger(logger lti_ports.Logger) lti_ports.NRPSOption {
+	return nrps.WithLogger(logger)
+}
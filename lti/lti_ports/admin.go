@@ -0,0 +1,7 @@
+package lti_ports
+
+import "net/http"
+
+// AdminAuthorizer decides whether a request may use the admin endpoints.
+// Returning false responds with 403 Forbidden.
+type AdminAuthorizer func(r *http.Request) bool
@@ -0,0 +1,23 @@
+package lti_ports
+
+import (
+	"context"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+type AGSOption func(AGS)
+
+// AGS is a client for the LTI Assignment and Grade Services. Every call is made
+// on behalf of the launch described by session, using its AGS endpoint claim.
+type AGS interface {
+	ListLineItems(ctx context.Context, session *lti_domain.LTIJWT, filter lti_domain.LineItemFilter) ([]lti_domain.LineItem, error)
+	GetLineItem(ctx context.Context, session *lti_domain.LTIJWT, lineItemURL string) (*lti_domain.LineItem, error)
+	CreateLineItem(ctx context.Context, session *lti_domain.LTIJWT, item lti_domain.LineItem) (*lti_domain.LineItem, error)
+	UpdateLineItem(ctx context.Context, session *lti_domain.LTIJWT, item lti_domain.LineItem) (*lti_domain.LineItem, error)
+	DeleteLineItem(ctx context.Context, session *lti_domain.LTIJWT, lineItemURL string) error
+
+	// PostScore publishes a score to lineItemURL. An empty lineItemURL uses the
+	// line item bound to the launch's resource link.
+	PostScore(ctx context.Context, session *lti_domain.LTIJWT, lineItemURL string, score lti_domain.Score) error
+}
@@ -18,6 +18,23 @@ type EphemeralStore interface {
 	SaveExchangeToken(ctx context.Context, exchangeToken string, data lti_domain.ExchangeToken, ttl time.Duration) error
 	ClaimExchangeToken(ctx context.Context, exchangeTokenID string, challenge string) (authToken string, err error)
 	GetAndDeleteExchangeToken(ctx context.Context, exchangeTokenID string) (*lti_domain.ExchangeToken, error)
+
+	// ConsumeNonce records a launch nonce as used for ttl. It returns
+	// lti_domain.ErrNonceReplayed if the nonce was already consumed.
+	ConsumeNonce(ctx context.Context, nonce string, ttl time.Duration) error
+
+	// SaveRevocation records that key was revoked at revokedAt, for ttl.
+	SaveRevocation(ctx context.Context, key string, revokedAt time.Time, ttl time.Duration) error
+	// LatestRevocation returns the most recent revocation recorded for any of
+	// keys, or the zero time if none of them was revoked.
+	LatestRevocation(ctx context.Context, keys ...string) (time.Time, error)
+
+	// SaveSessionClaims keeps the claims of a session too large for its token
+	// under key, for ttl.
+	SaveSessionClaims(ctx context.Context, key string, claims lti_domain.SessionClaims, ttl time.Duration) error
+	// GetSessionClaims returns the claims saved under key. It returns
+	// lti_domain.ErrSessionClaimsNotFound once they have expired.
+	GetSessionClaims(ctx context.Context, key string) (*lti_domain.SessionClaims, error)
 }
 
 type EphemeralRegistry interface {
@@ -2,9 +2,27 @@ package lti_ports
 
 import (
 	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
 )
 
 type FallbackAuthorizer interface {
 	HandleFallback(w http.ResponseWriter, r *http.Request, exchangeToken string)
 	Route() *http.ServeMux
 }
+
+// PlatformStorageAuthorizer is a FallbackAuthorizer that binds launches to the
+// browser through the platform's postMessage storage (lti_storage_target), so
+// those launches don't depend on the confirmation cookie.
+type PlatformStorageAuthorizer interface {
+	FallbackAuthorizer
+
+	// StoreLogin writes a page that saves the login in the platform's frame
+	// and then sends the browser to redirect.
+	StoreLogin(w http.ResponseWriter, r *http.Request, login lti_domain.StorageLogin, redirect string) error
+
+	// VerifyLaunch reads the login back from the platform's frame. It writes
+	// a lookup page and returns StorageCheckPending the first time it sees a
+	// launch.
+	VerifyLaunch(w http.ResponseWriter, r *http.Request, login lti_domain.StorageLogin) lti_domain.StorageCheck
+}
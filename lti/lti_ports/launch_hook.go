@@ -0,0 +1,38 @@
+package lti_ports
+
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+// LaunchHook runs after a launch is validated and before its session is
+// issued. It may enrich session in place, for example to map the LMS user to
+// an internal ID or to add Extra claims. Returning an error stops the launch;
+// return lti_domain.DenyLaunch to show the user why.
+//
+// claims holds the platform's id_token claims. It is nil for imposter
+// launches, which carry no id_token.
+type LaunchHook interface {
+	OnLaunch(ctx context.Context, claims jwt.MapClaims, session *lti_domain.LTIJWT) error
+}
+
+// LaunchHookFunc adapts a function to a LaunchHook.
+type LaunchHookFunc func(ctx context.Context, claims jwt.MapClaims, session *lti_domain.LTIJWT) error
+
+func (f LaunchHookFunc) OnLaunch(ctx context.Context, claims jwt.MapClaims, session *lti_domain.LTIJWT) error {
+	return f(ctx, claims, session)
+}
+
+// LaunchHooks runs each hook in order, stopping at the first error.
+type LaunchHooks []LaunchHook
+
+func (h LaunchHooks) OnLaunch(ctx context.Context, claims jwt.MapClaims, session *lti_domain.LTIJWT) error {
+	for _, hook := range h {
+		if err := hook.OnLaunch(ctx, claims, session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
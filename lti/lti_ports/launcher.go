@@ -16,6 +16,13 @@ type Launcher interface {
 	HandleAuthFallback(w http.ResponseWriter, r *http.Request)
 }
 
+// SessionRefresher is implemented by launchers that can extend a verified
+// session. HandleRefresh runs behind the refresh verifier, so the session is
+// already in the request context.
+type SessionRefresher interface {
+	HandleRefresh(w http.ResponseWriter, r *http.Request)
+}
+
 type Keyfunc interface {
 	Keyfunc(token *jwt.Token) (any, error)
 }
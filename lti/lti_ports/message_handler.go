@@ -0,0 +1,32 @@
+package lti_ports
+
+import (
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+// MessageHandler finishes launches of one LTI message type once the launcher
+// has validated the id_token and built the session.
+type MessageHandler interface {
+	HandleMessage(w http.ResponseWriter, r *http.Request, launch *lti_domain.Launch, sessions SessionIssuer)
+}
+
+// MessageHandlerFunc adapts a function to a MessageHandler.
+type MessageHandlerFunc func(w http.ResponseWriter, r *http.Request, launch *lti_domain.Launch, sessions SessionIssuer)
+
+func (f MessageHandlerFunc) HandleMessage(w http.ResponseWriter, r *http.Request, launch *lti_domain.Launch, sessions SessionIssuer) {
+	f(w, r, launch, sessions)
+}
+
+// SessionIssuer gives message handlers the launcher's ways of issuing the
+// session for a launch.
+type SessionIssuer interface {
+	// StartSession sends the browser into the app at to through the swap flow,
+	// which sets the session cookie. An empty to picks the destination from
+	// the launcher's launch routes, or the app root when none match.
+	StartSession(w http.ResponseWriter, r *http.Request, session *lti_domain.LTIJWT, to string)
+	// SignSession stamps session with its IDs and expiry and signs it, for
+	// handlers that hand the token over themselves.
+	SignSession(session *lti_domain.LTIJWT) (string, error)
+}
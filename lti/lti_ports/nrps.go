@@ -0,0 +1,16 @@
+package lti_ports
+
+import (
+	"context"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+type NRPSOption func(NRPS)
+
+// NRPS is a client for the LTI Names and Role Provisioning Services. Every call
+// is made on behalf of the launch described by session.
+type NRPS interface {
+	GetMemberships(ctx context.Context, session *lti_domain.LTIJWT, filter lti_domain.MembershipFilter) (*lti_domain.Membership, error)
+	GetMembershipDifferences(ctx context.Context, session *lti_domain.LTIJWT, differencesURL string) (*lti_domain.Membership, error)
+}
@@ -0,0 +1,23 @@
+package lti_ports
+
+import (
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+type DynamicRegistrationOption func(DynamicRegistration)
+
+// DynamicRegistration implements the tool side of IMS LTI Dynamic Registration.
+// The platform opens the registration route with an openid_configuration URL;
+// the handler registers tool with the platform and persists the deployment.
+type DynamicRegistration interface {
+	HandleRegistration(w http.ResponseWriter, r *http.Request, tool lti_domain.ToolRegistration)
+}
+
+// LauncherDescriptor is implemented by launchers that can describe themselves
+// for Dynamic Registration.
+type LauncherDescriptor interface {
+	GetBaseURL() string
+	GetEnabledServices() []lti_domain.LTIService
+}
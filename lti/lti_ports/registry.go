@@ -10,5 +10,16 @@ type Registry interface {
 	// Find a deployment
 	GetDeployment(ctx context.Context, clientID string, deploymentID string) (lti_domain.Deployment, error)
 
-	AddDeployment(ctx context.Context, dep lti_domain.Deployment)
+	// List deployments matching the filter. An empty filter lists everything.
+	ListDeployments(ctx context.Context, filter lti_domain.DeploymentFilter) ([]lti_domain.Deployment, error)
+
+	// Add a deployment, replacing any existing one with the same client and
+	// deployment ID.
+	AddDeployment(ctx context.Context, dep lti_domain.Deployment) error
+
+	// Replace an existing deployment. Returns ErrDeploymentNotFound if missing.
+	UpdateDeployment(ctx context.Context, dep lti_domain.Deployment) error
+
+	// Remove a deployment. Returns ErrDeploymentNotFound if missing.
+	RemoveDeployment(ctx context.Context, clientID string, deploymentID string) error
 }
@@ -0,0 +1,21 @@
+package lti_ports
+
+import (
+	"context"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+type SessionRevokerOption func(SessionRevoker)
+
+// SessionRevoker ends sessions before their JWT expires. Revoking a session
+// covers every token refreshed from it; revoking a user or tenant covers every
+// session that started before the call.
+type SessionRevoker interface {
+	RevokeSession(ctx context.Context, session *lti_domain.LTIJWT) error
+	RevokeUser(ctx context.Context, tenantID, userID string) error
+	RevokeTenant(ctx context.Context, tenantID string) error
+
+	// CheckSession returns lti_domain.ErrSessionRevoked if session was revoked.
+	CheckSession(ctx context.Context, session *lti_domain.LTIJWT) error
+}
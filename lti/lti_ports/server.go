@@ -13,6 +13,7 @@ type Server interface {
 
 	GetLauncher() Launcher
 	GetVerifier() Verifier
+	GetRoutes() lti_domain.Routes
 }
 
 type VerifyTokenFunc func(verifier Verifier, expectedAudience []string, allowImpostering bool, next http.Handler) http.Handler
@@ -24,4 +25,7 @@ type ProtectedRoute struct {
 	Handler                http.Handler
 	Verifier               VerifyTokenFunc
 	AllowImpostering       bool
+	// LaunchTypes limits the route to sessions started by these message
+	// types. Empty allows any.
+	LaunchTypes []lti_domain.LTIService
 }
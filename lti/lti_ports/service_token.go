@@ -0,0 +1,15 @@
+package lti_ports
+
+import (
+	"context"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+)
+
+type ServiceTokenOption func(ServiceTokenProvider)
+
+// ServiceTokenProvider obtains OAuth2 access tokens for LTI Advantage services
+// (AGS, NRPS, ...) from a deployment's platform token endpoint.
+type ServiceTokenProvider interface {
+	AccessToken(ctx context.Context, dep lti_domain.Deployment, scopes []string) (string, error)
+}
@@ -0,0 +1,58 @@
+package lti_registration
+
+import (
+	"net/http"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/registration"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// TenantResolver authorizes a registration and picks the tenant the
+// deployment belongs to. Returning an error or an empty tenant rejects the
+// registration.
+type TenantResolver = func(r *http.Request, platform lti_domain.PlatformConfiguration) (string, error)
+
+// NewDynamicRegistration returns an LTI Dynamic Registration handler. Mount it
+// with lti_http.WithDynamicRegistration. WithRegistry and WithTenantResolver
+// are required; the registration route is public, so the resolver decides
+// which platforms may register.
+func NewDynamicRegistration(opts ...lti_ports.DynamicRegistrationOption) lti_ports.DynamicRegistration {
+	return registration.NewDynamicRegistration(opts...)
+}
+
+// WithRegistry sets the registry that registered deployments are saved to.
+func WithRegistry(registry lti_ports.Registry) lti_ports.DynamicRegistrationOption {
+	return registration.WithRegistry(registry)
+}
+
+// WithClientName sets the tool name shown to platform administrators.
+func WithClientName(name string) lti_ports.DynamicRegistrationOption {
+	return registration.WithClientName(name)
+}
+
+// WithScopes requests LTI Advantage scopes (e.g. AGS, NRPS) during registration.
+func WithScopes(scopes ...string) lti_ports.DynamicRegistrationOption {
+	return registration.WithScopes(scopes...)
+}
+
+// WithTenantResolver authorizes registrations and assigns the deployment to a
+// tenant. A platform can only re-register deployments it registered itself;
+// re-registration keeps the deployment's tenant.
+func WithTenantResolver(resolver TenantResolver) lti_ports.DynamicRegistrationOption {
+	return registration.WithTenantResolver(resolver)
+}
+
+// WithAllowedIssuers only accepts registrations from platforms whose issuer
+// has the same origin as one of issuers, e.g. "https://canvas.instructure.com".
+func WithAllowedIssuers(issuers ...string) lti_ports.DynamicRegistrationOption {
+	return registration.WithAllowedIssuers(issuers...)
+}
+
+func WithHTTPClient(client *http.Client) lti_ports.DynamicRegistrationOption {
+	return registration.WithHTTPClient(client)
+}
+
+func WithLogger(logger lti_ports.Logger) lti_ports.DynamicRegistrationOption {
+	return registration.WithLogger(logger)
+}
@@ -0,0 +1,30 @@
+package lti_registry
+
+import (
+	"github.com/redis/go-redis/v9"
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/registry"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// RedisOption represents a configurable option for the Redis ephemeral store.
+type RedisOption struct {
+	toInternal func() registry.RedisOption
+}
+
+// NewRedisStore returns an EphemeralStore backed by Redis, suitable for running
+// multiple replicas behind a load balancer. Pair it with a persistent Registry
+// for deployments.
+func NewRedisStore(client redis.UniversalClient, opts ...RedisOption) lti_ports.EphemeralStore {
+	internalOpts := []registry.RedisOption{}
+	for _, opt := range opts {
+		internalOpts = append(internalOpts, opt.toInternal())
+	}
+	return registry.NewRedisStore(client, internalOpts...)
+}
+
+// WithKeyPrefix namespaces every key written by the store. Defaults to "lti:".
+func WithKeyPrefix(prefix string) RedisOption {
+	return RedisOption{toInternal: func() registry.RedisOption {
+		return registry.WithKeyPrefix(prefix)
+	}}
+}
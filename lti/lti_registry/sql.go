@@ -0,0 +1,47 @@
+package lti_registry
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/registry"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// Dialect selects the SQL flavor spoken by the registry's database.
+type Dialect = registry.Dialect
+
+const (
+	DialectPostgres = registry.DialectPostgres
+	DialectSQLite   = registry.DialectSQLite
+)
+
+// SQLOption represents a configurable option for the SQL registry.
+type SQLOption struct {
+	toInternal func() registry.SQLOption
+}
+
+// NewSQLRegistry returns a persistent Registry backed by database/sql. Pending
+// schema migrations are applied before it returns. The caller owns db and is
+// responsible for importing the matching driver.
+func NewSQLRegistry(ctx context.Context, db *sql.DB, opts ...SQLOption) (lti_ports.Registry, error) {
+	internalOpts := []registry.SQLOption{}
+	for _, opt := range opts {
+		internalOpts = append(internalOpts, opt.toInternal())
+	}
+	return registry.NewSQLRegistry(ctx, db, internalOpts...)
+}
+
+// WithDialect sets the SQL dialect. Defaults to DialectPostgres.
+func WithDialect(dialect Dialect) SQLOption {
+	return SQLOption{toInternal: func() registry.SQLOption {
+		return registry.WithDialect(dialect)
+	}}
+}
+
+// WithLogger sets the logger used for migrations and write failures.
+func WithLogger(logger lti_ports.Logger) SQLOption {
+	return SQLOption{toInternal: func() registry.SQLOption {
+		return registry.WithSQLLogger(logger)
+	}}
+}
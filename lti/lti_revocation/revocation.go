@@ -0,0 +1,35 @@
+package lti_revocation
+
+import (
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/revocation"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_domain"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// NewRevoker returns a SessionRevoker that keeps revocations in an
+// EphemeralStore. Pass it to lti_http.WithSessionRevoker so protected routes
+// reject revoked sessions, and call RevokeUser or RevokeTenant from your own
+// admin tooling to end every session of a user or tenant.
+func NewRevoker(opts ...lti_ports.SessionRevokerOption) lti_ports.SessionRevoker {
+	return revocation.NewRevoker(opts...)
+}
+
+// WithStore sets where revocations are kept. Use the same store as the
+// launcher so every replica sees them.
+func WithStore(store lti_ports.EphemeralStore) lti_ports.SessionRevokerOption {
+	return revocation.WithStore(store)
+}
+
+// WithPolicy must match the launcher's policy; MaxSessionLifetime decides how
+// long revocations are kept. Defaults to lti_domain.DefaultPolicy().
+func WithPolicy(policy lti_domain.Policy) lti_ports.SessionRevokerOption {
+	return revocation.WithPolicy(policy)
+}
+
+func WithClock(clock lti_ports.Clock) lti_ports.SessionRevokerOption {
+	return revocation.WithClock(clock)
+}
+
+func WithLogger(logger lti_ports.Logger) lti_ports.SessionRevokerOption {
+	return revocation.WithLogger(logger)
+}
@@ -0,0 +1,39 @@
+package lti_servicetoken
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/internal/adapters/servicetoken"
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+// NewClientCredentialsProvider returns a ServiceTokenProvider that exchanges a
+// signed JWT client assertion for scoped access tokens at each deployment's
+// token endpoint. Tokens are cached per deployment and scope set until they expire.
+func NewClientCredentialsProvider(opts ...lti_ports.ServiceTokenOption) lti_ports.ServiceTokenProvider {
+	return servicetoken.NewClientCredentials(opts...)
+}
+
+// WithSigner sets the signer used for the client assertion. Its public key
+// must be published on the tool's JWKS URL.
+func WithSigner(signer lti_ports.AsymetricSigner) lti_ports.ServiceTokenOption {
+	return servicetoken.WithSigner(signer)
+}
+
+func WithHTTPClient(client *http.Client) lti_ports.ServiceTokenOption {
+	return servicetoken.WithHTTPClient(client)
+}
+
+func WithClock(clock lti_ports.Clock) lti_ports.ServiceTokenOption {
+	return servicetoken.WithClock(clock)
+}
+
+// WithExpiryMargin sets how long before expiry a cached token is refreshed. Defaults to 30s.
+func WithExpiryMargin(margin time.Duration) lti_ports.ServiceTokenOption {
+	return servicetoken.WithExpiryMargin(margin)
+}
+
+func WithLogger(logger lti_ports.Logger) lti_ports.ServiceTokenOption {
+	return servicetoken.WithLogger(logger)
+}
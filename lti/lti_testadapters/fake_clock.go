@@ -0,0 +1,33 @@
+package lti_testadapters
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vizdos-enterprises/go-lti/lti/lti_ports"
+)
+
+var _ lti_ports.Clock = (*FakeClock)(nil)
+
+// FakeClock is a manually advanced clock for expiry tests.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -22,14 +24,27 @@ type FakeRegistry struct {
 	Deployments    sync.Map
 	Swaps          sync.Map
 	ExchangeTokens sync.Map
+	Nonces         sync.Map
+	Revocations    sync.Map // key -> time.Time
+	SessionClaims  sync.Map // key -> lti_domain.SessionClaims
 
 	lastSavedExchangeTokenID string
+	lastStateTTL             time.Duration
+	lastSwapTokenTTL         time.Duration
 }
 
 func (f *FakeRegistry) GetLastSavedExchangeTokenID() string {
 	return f.lastSavedExchangeTokenID
 }
 
+func (f *FakeRegistry) GetLastStateTTL() time.Duration {
+	return f.lastStateTTL
+}
+
+func (f *FakeRegistry) GetLastSwapTokenTTL() time.Duration {
+	return f.lastSwapTokenTTL
+}
+
 func (f *FakeRegistry) GetDeployment(_ context.Context, clientID, depID string) (lti_domain.Deployment, error) {
 	v, ok := f.Deployments.Load(depID)
 	if !ok {
@@ -39,11 +54,43 @@ func (f *FakeRegistry) GetDeployment(_ context.Context, clientID, depID string)
 	return dep, nil
 }
 
-func (f *FakeRegistry) AddDeployment(ctx context.Context, dep lti_domain.Deployment) {
+func (f *FakeRegistry) ListDeployments(_ context.Context, filter lti_domain.DeploymentFilter) ([]lti_domain.Deployment, error) {
+	out := []lti_domain.Deployment{}
+	f.Deployments.Range(func(_, v any) bool {
+		dep := v.(lti_domain.Deployment)
+		if filter.Matches(dep) {
+			out = append(out, dep)
+		}
+		return true
+	})
+	slices.SortFunc(out, func(a, b lti_domain.Deployment) int {
+		return strings.Compare(a.GetDeploymentID(), b.GetDeploymentID())
+	})
+	return out, nil
+}
+
+func (f *FakeRegistry) AddDeployment(ctx context.Context, dep lti_domain.Deployment) error {
 	f.Deployments.Store(dep.GetDeploymentID(), dep)
+	return nil
+}
+
+func (f *FakeRegistry) UpdateDeployment(_ context.Context, dep lti_domain.Deployment) error {
+	if _, ok := f.Deployments.Load(dep.GetDeploymentID()); !ok {
+		return lti_domain.ErrDeploymentNotFound
+	}
+	f.Deployments.Store(dep.GetDeploymentID(), dep)
+	return nil
+}
+
+func (f *FakeRegistry) RemoveDeployment(_ context.Context, clientID, depID string) error {
+	if _, ok := f.Deployments.LoadAndDelete(depID); !ok {
+		return lti_domain.ErrDeploymentNotFound
+	}
+	return nil
 }
 
-func (f *FakeRegistry) SaveState(_ context.Context, key string, value lti_domain.State, _ time.Duration) error {
+func (f *FakeRegistry) SaveState(_ context.Context, key string, value lti_domain.State, ttl time.Duration) error {
+	f.lastStateTTL = ttl
 	f.States.Store(key, value)
 	return nil
 }
@@ -60,7 +107,44 @@ func (f *FakeRegistry) DeleteState(_ context.Context, key string) error {
 	return nil
 }
 
-func (f *FakeRegistry) SaveSwapToken(_ context.Context, swapToken string, data lti_domain.SwapToken, _ time.Duration) error {
+func (f *FakeRegistry) ConsumeNonce(_ context.Context, nonce string, _ time.Duration) error {
+	if _, loaded := f.Nonces.LoadOrStore(nonce, struct{}{}); loaded {
+		return lti_domain.ErrNonceReplayed
+	}
+	return nil
+}
+
+func (f *FakeRegistry) SaveRevocation(_ context.Context, key string, revokedAt time.Time, _ time.Duration) error {
+	f.Revocations.Store(key, revokedAt)
+	return nil
+}
+
+func (f *FakeRegistry) LatestRevocation(_ context.Context, keys ...string) (time.Time, error) {
+	var latest time.Time
+	for _, key := range keys {
+		if v, ok := f.Revocations.Load(key); ok && v.(time.Time).After(latest) {
+			latest = v.(time.Time)
+		}
+	}
+	return latest, nil
+}
+
+func (f *FakeRegistry) SaveSessionClaims(_ context.Context, key string, claims lti_domain.SessionClaims, _ time.Duration) error {
+	f.SessionClaims.Store(key, claims)
+	return nil
+}
+
+func (f *FakeRegistry) GetSessionClaims(_ context.Context, key string) (*lti_domain.SessionClaims, error) {
+	v, ok := f.SessionClaims.Load(key)
+	if !ok {
+		return nil, lti_domain.ErrSessionClaimsNotFound
+	}
+	claims := v.(lti_domain.SessionClaims)
+	return &claims, nil
+}
+
+func (f *FakeRegistry) SaveSwapToken(_ context.Context, swapToken string, data lti_domain.SwapToken, ttl time.Duration) error {
+	f.lastSwapTokenTTL = ttl
 	f.Swaps.Store(swapToken, &data)
 	return nil
 }